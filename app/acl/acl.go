@@ -0,0 +1,340 @@
+package acl
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultUserName은 AUTH에 사용자 이름 없이 비밀번호만 보냈을 때 쓰는 사용자입니다
+const DefaultUserName = "default"
+
+// ACL은 사용자 목록과 ACL LOG를 관리합니다
+type ACL struct {
+	mu    sync.RWMutex
+	table CommandTable
+	users map[string]*User
+
+	log       []*LogEntry // 최신 항목이 앞에 옵니다
+	logMaxLen int
+	nextLogID int64
+}
+
+func New(table CommandTable) *ACL {
+	a := &ACL{
+		table:     table,
+		users:     make(map[string]*User),
+		logMaxLen: defaultLogMaxLen,
+	}
+	a.users[DefaultUserName] = newDefaultUser(table)
+	return a
+}
+
+// newDefaultUser는 모든 권한을 가진 nopass default 사용자를 만듭니다
+func newDefaultUser(table CommandTable) *User {
+	user := newUser(DefaultUserName)
+	for _, rule := range []string{"on", "nopass", "~*", "&*", "+@all"} {
+		_ = user.setRule(rule, table)
+	}
+	return user
+}
+
+// DefaultUser는 새 연결에 붙일 default 사용자와, 그 연결이 AUTH 없이 인증된 상태로 시작하는지를 반환합니다
+func (a *ACL) DefaultUser() (*User, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	user := a.users[DefaultUserName]
+	return user, user.enabled && user.nopass
+}
+
+// DefaultUserNoPass는 default 사용자가 비밀번호 없이 열려 있는지 반환합니다
+func (a *ACL) DefaultUserNoPass() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.users[DefaultUserName].nopass
+}
+
+// SetRequirePass는 requirepass 값을 default 사용자의 비밀번호로 반영합니다
+func (a *ACL) SetRequirePass(password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	user := a.users[DefaultUserName]
+	if password == "" {
+		_ = user.setRule("nopass", a.table)
+		return
+	}
+	_ = user.setRule("resetpass", a.table)
+	_ = user.setRule(">"+password, a.table)
+}
+
+// Authenticate는 사용자 이름과 비밀번호를 확인합니다
+func (a *ACL) Authenticate(username, password string) (*User, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	user, ok := a.users[username]
+	if !ok || !user.enabled || !user.checkPassword(password) {
+		return nil, false
+	}
+	return user, true
+}
+
+// SetUser는 사용자를 만들거나 규칙을 적용합니다
+// 규칙 하나라도 잘못되면 아무것도 바꾸지 않습니다
+func (a *ACL) SetUser(name string, rules []string) error {
+	if strings.ContainsAny(name, " \x00") {
+		return fmt.Errorf("Usernames can't contain spaces or null characters")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	existing, ok := a.users[name]
+	var user *User
+	if ok {
+		user = existing.clone()
+	} else {
+		user = newUser(name)
+	}
+
+	for _, rule := range rules {
+		if err := user.setRule(rule, a.table); err != nil {
+			return fmt.Errorf("Error in ACL SETUSER modifier '%s': %v", rule, err)
+		}
+	}
+
+	if ok {
+		// 이 사용자로 로그인한 연결이 바뀐 규칙을 바로 따르도록 제자리에서 고칩니다
+		*existing = *user
+	} else {
+		a.users[name] = user
+	}
+	return nil
+}
+
+// DelUser는 사용자들을 지우고 실제로 지운 수를 반환합니다
+// 지운 사용자로 로그인해 있던 연결은 모든 권한을 잃습니다
+func (a *ACL) DelUser(names []string) (int, error) {
+	for _, name := range names {
+		if name == DefaultUserName {
+			return 0, fmt.Errorf("The 'default' user cannot be removed")
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	deleted := 0
+	for _, name := range names {
+		if user, ok := a.users[name]; ok {
+			_ = user.setRule("reset", a.table)
+			delete(a.users, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// UserInfo는 ACL GETUSER 응답에 쓰는 사용자 정보입니다
+type UserInfo struct {
+	Flags     []string
+	Passwords []string
+	Commands  string
+	Keys      string
+	Channels  string
+}
+
+func (a *ACL) GetUser(name string) (UserInfo, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	user, ok := a.users[name]
+	if !ok {
+		return UserInfo{}, false
+	}
+	channels := user.describeChannels()
+	if channels == "resetchannels" {
+		channels = ""
+	}
+	return UserInfo{
+		Flags:     user.flags(),
+		Passwords: append([]string{}, user.passwords...),
+		Commands:  user.describeCommands(),
+		Keys:      user.describeKeys(),
+		Channels:  channels,
+	}, true
+}
+
+// Usernames는 사용자 이름을 정렬해서 반환합니다
+func (a *ACL) Usernames() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	names := make([]string, 0, len(a.users))
+	for name := range a.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List는 ACL LIST 응답 (사용자마다 "user ..." 한 줄)을 반환합니다
+func (a *ACL) List() []string {
+	names := a.Usernames()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		if user, ok := a.users[name]; ok {
+			lines = append(lines, user.describe())
+		}
+	}
+	return lines
+}
+
+// Categories는 ACL CAT 응답에 쓰는 카테고리 목록입니다
+func (a *ACL) Categories() []string {
+	return a.table.Categories()
+}
+
+// CommandsInCategory는 카테고리에 속한 명령어를 반환합니다
+func (a *ACL) CommandsInCategory(category string) ([]string, bool) {
+	for _, c := range a.table.Categories() {
+		if strings.EqualFold(c, category) {
+			return a.table.CommandsInCategory(c), true
+		}
+	}
+	return nil, false
+}
+
+// PermissionError는 ACL 검사에 실패한 이유입니다
+type PermissionError struct {
+	Reason string // command, key, channel
+	Object string // 거부된 명령어, 키 또는 채널
+	User   string
+}
+
+func (e *PermissionError) Error() string {
+	switch e.Reason {
+	case "key":
+		return "NOPERM No permissions to access a key"
+	case "channel":
+		return "NOPERM No permissions to access a channel"
+	default:
+		return fmt.Sprintf("NOPERM User %s has no permissions to run the '%s' command", e.User, e.Object)
+	}
+}
+
+// Check는 사용자가 명령어를 주어진 키로 실행할 수 있는지 확인합니다
+// user가 nil이면 (레플리카의 마스터 연결) 모든 권한을 가진 것으로 봅니다
+func (a *ACL) Check(user *User, command string, keys [][]byte) *PermissionError {
+	if user == nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !user.canRun(command) {
+		return &PermissionError{Reason: "command", Object: command, User: user.name}
+	}
+	for _, key := range keys {
+		if !user.canAccessKey(key) {
+			return &PermissionError{Reason: "key", Object: string(key), User: user.name}
+		}
+	}
+	return nil
+}
+
+// CheckChannel은 사용자가 채널(또는 패턴)에 접근할 수 있는지 확인합니다
+func (a *ACL) CheckChannel(user *User, channel string, isPattern bool) *PermissionError {
+	if user == nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !user.canAccessChannel(channel, isPattern) {
+		return &PermissionError{Reason: "channel", Object: channel, User: user.name}
+	}
+	return nil
+}
+
+// LoadFile은 aclfile을 읽어 사용자 목록을 통째로 바꿉니다
+// 한 줄이라도 잘못되면 아무것도 바꾸지 않습니다
+func (a *ACL) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Error loading ACLs, opening file '%s': %v", path, err)
+	}
+	defer file.Close()
+
+	users := map[string]*User{DefaultUserName: newDefaultUser(a.table)}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if fields[0] != "user" || len(fields) < 2 {
+			return fmt.Errorf("%s:%d: line should start with user keyword", path, lineNum)
+		}
+		name := fields[1]
+		if name != DefaultUserName {
+			if _, ok := users[name]; ok {
+				return fmt.Errorf("%s:%d: duplicate user '%s' found", path, lineNum, name)
+			}
+		}
+
+		user := newUser(name)
+		for _, rule := range fields[2:] {
+			if err := user.setRule(rule, a.table); err != nil {
+				return fmt.Errorf("%s:%d: %v", path, lineNum, err)
+			}
+		}
+		users[name] = user
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Error loading ACLs, reading file '%s': %v", path, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, user := range a.users {
+		if loaded, ok := users[name]; ok {
+			*user = *loaded
+			users[name] = user
+		} else {
+			_ = user.setRule("reset", a.table)
+		}
+	}
+	a.users = users
+	return nil
+}
+
+// SaveFile은 현재 사용자 목록을 aclfile에 씁니다
+// 임시 파일에 쓴 뒤 이름을 바꾸므로 중간에 실패해도 기존 파일은 남습니다
+func (a *ACL) SaveFile(path string) error {
+	var sb strings.Builder
+	for _, line := range a.List() {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), 0600); err != nil {
+		return fmt.Errorf("There was an error trying to save the ACLs. Please check the server logs for more information")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("There was an error trying to save the ACLs. Please check the server logs for more information")
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package acl
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTable은 테스트용 명령어 표입니다
+type fakeTable map[string][]string
+
+func (t fakeTable) HasCommand(name string) bool {
+	parent, _, _ := strings.Cut(name, "|")
+	for command := range t {
+		if p, _, _ := strings.Cut(command, "|"); p == parent {
+			return true
+		}
+	}
+	return false
+}
+
+func (t fakeTable) CommandsInCategory(category string) []string {
+	var names []string
+	for command, categories := range t {
+		if slices.Contains(categories, category) {
+			names = append(names, command)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (t fakeTable) Categories() []string {
+	return []string{"read", "write", "admin", "dangerous"}
+}
+
+var table = fakeTable{
+	"get":         {"read"},
+	"set":         {"write"},
+	"acl|whoami":  {},
+	"acl|setuser": {"admin", "dangerous"},
+}
+
+func TestSetUserAndCheck(t *testing.T) {
+	a := New(table)
+	if err := a.SetUser("alice", []string{"on", ">secret", "~cache:*", "+@read"}); err != nil {
+		t.Fatalf("SetUser 실패: %v", err)
+	}
+
+	user, ok := a.Authenticate("alice", "secret")
+	if !ok {
+		t.Fatalf("올바른 비밀번호로 인증되어야 함")
+	}
+	if _, ok := a.Authenticate("alice", "wrong"); ok {
+		t.Errorf("틀린 비밀번호로 인증되면 안 됨")
+	}
+
+	for _, tc := range []struct {
+		command string
+		key     string
+		reason  string
+	}{
+		{"get", "cache:1", ""},
+		{"get", "session:1", "key"},
+		{"set", "cache:1", "command"},
+		{"acl|setuser", "", "command"},
+	} {
+		var keys [][]byte
+		if tc.key != "" {
+			keys = [][]byte{[]byte(tc.key)}
+		}
+		err := a.Check(user, tc.command, keys)
+		if tc.reason == "" && err != nil {
+			t.Errorf("%s %s 는 허용되어야 함. got=%v", tc.command, tc.key, err)
+		}
+		if tc.reason != "" && (err == nil || err.Reason != tc.reason) {
+			t.Errorf("%s %s 는 %s 이유로 거부되어야 함. got=%v", tc.command, tc.key, tc.reason, err)
+		}
+	}
+
+	// 이미 로그인한 사용자의 권한도 바로 바뀌어야 합니다
+	if err := a.SetUser("alice", []string{"+set"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Check(user, "set", [][]byte{[]byte("cache:1")}); err != nil {
+		t.Errorf("+set 뒤에는 허용되어야 함. got=%v", err)
+	}
+}
+
+func TestSubcommandRules(t *testing.T) {
+	a := New(table)
+	if err := a.SetUser("bob", []string{"on", "nopass", "+acl", "-acl|setuser"}); err != nil {
+		t.Fatal(err)
+	}
+	user, _ := a.Authenticate("bob", "")
+	if err := a.Check(user, "acl|whoami", nil); err != nil {
+		t.Errorf("acl|whoami는 허용되어야 함. got=%v", err)
+	}
+	if err := a.Check(user, "acl|setuser", nil); err == nil {
+		t.Errorf("acl|setuser는 거부되어야 함")
+	}
+
+	// 부모 명령어 규칙은 이전 서브커맨드 규칙을 덮어씁니다
+	if err := a.SetUser("bob", []string{"+acl"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Check(user, "acl|setuser", nil); err != nil {
+		t.Errorf("+acl 뒤에는 acl|setuser도 허용되어야 함. got=%v", err)
+	}
+}
+
+func TestSetUserErrors(t *testing.T) {
+	a := New(table)
+	for rule, want := range map[string]string{
+		"+nosuch":  "Unknown command or category name in ACL",
+		"+@nosuch": "Unknown command or category name in ACL",
+		"#abc":     "The password hash must be exactly 64 characters",
+		"<missing": "The password you are trying to remove",
+		"bogus":    "Syntax error",
+	} {
+		err := a.SetUser("carol", []string{rule})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("규칙 %q 에러가 다름. got=%v, want=%s", rule, err, want)
+		}
+	}
+
+	if err := a.SetUser("carol", []string{"allkeys", "~foo"}); err == nil {
+		t.Errorf("allkeys 뒤에 키 패턴을 추가하면 에러가 나야 함")
+	}
+
+	// 실패한 SETUSER는 아무것도 바꾸지 않습니다
+	if err := a.SetUser("dave", []string{"on", "+nosuch"}); err == nil {
+		t.Fatal("에러가 나야 함")
+	}
+	if _, ok := a.GetUser("dave"); ok {
+		t.Errorf("실패한 SETUSER로 사용자가 만들어지면 안 됨")
+	}
+
+	if _, err := a.DelUser([]string{"default"}); err == nil {
+		t.Errorf("default 사용자는 지울 수 없어야 함")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	a := New(table)
+	if err := a.SetUser("alice", []string{"on", ">secret", "~cache:*", "&news.*", "+@read", "-get"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"user alice on #" + hashPassword("secret") + " ~cache:* &news.* -@all +@read -get",
+		"user default on nopass ~* &* +@all",
+	}
+	if got := a.List(); !slices.Equal(got, want) {
+		t.Errorf("ACL LIST가 다름.\ngot=%q\nwant=%q", got, want)
+	}
+}
+
+func TestRequirePass(t *testing.T) {
+	a := New(table)
+	if _, authenticated := a.DefaultUser(); !authenticated {
+		t.Errorf("requirepass가 없으면 바로 인증되어야 함")
+	}
+	a.SetRequirePass("secret")
+	if _, authenticated := a.DefaultUser(); authenticated {
+		t.Errorf("requirepass가 있으면 AUTH가 필요해야 함")
+	}
+	if _, ok := a.Authenticate(DefaultUserName, "secret"); !ok {
+		t.Errorf("requirepass로 default 사용자 인증이 되어야 함")
+	}
+}
+
+func TestLoadSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.acl")
+	a := New(table)
+	if err := a.SetUser("alice", []string{"on", ">secret", "~cache:*", "+get"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile 실패: %v", err)
+	}
+
+	b := New(table)
+	if err := b.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile 실패: %v", err)
+	}
+	if !slices.Equal(a.List(), b.List()) {
+		t.Errorf("저장 후 읽은 사용자가 다름.\ngot=%q\nwant=%q", b.List(), a.List())
+	}
+
+	if err := os.WriteFile(path, []byte("user alice on\nuser bob +nosuch\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LoadFile(path); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("잘못된 줄 번호가 에러에 있어야 함. got=%v", err)
+	}
+	if _, ok := b.GetUser("alice"); !ok {
+		t.Errorf("실패한 LOAD는 기존 사용자를 바꾸면 안 됨")
+	}
+}
+
+func TestLogGrouping(t *testing.T) {
+	a := New(table)
+	current := time.Unix(1000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	a.AddLogEntry("command", "toplevel", "set", "alice", "addr=a")
+	a.AddLogEntry("command", "toplevel", "set", "alice", "addr=b")
+	a.AddLogEntry("key", "toplevel", "secret", "alice", "addr=b")
+
+	entries := a.Log(-1)
+	if len(entries) != 2 || entries[0].Reason != "key" || entries[1].Count != 2 {
+		t.Fatalf("같은 항목은 묶여야 함. got=%+v", entries)
+	}
+
+	current = current.Add(2 * logGroupingWindow)
+	a.AddLogEntry("command", "toplevel", "set", "alice", "addr=a")
+	if entries := a.Log(-1); len(entries) != 3 {
+		t.Errorf("시간이 지나면 새 항목이 만들어져야 함. got=%d", len(entries))
+	}
+
+	a.ResetLog()
+	if entries := a.Log(-1); len(entries) != 0 {
+		t.Errorf("ACL LOG RESET 뒤에는 비어 있어야 함")
+	}
+}
@@ -0,0 +1,91 @@
+package acl
+
+import "time"
+
+const (
+	defaultLogMaxLen = 128
+	// 같은 이유로 거부된 항목은 이 시간 안이면 새 항목을 만들지 않고 count만 늘립니다
+	logGroupingWindow = 60 * time.Second
+)
+
+// LogEntry는 ACL LOG 항목 하나입니다
+type LogEntry struct {
+	Count      int
+	Reason     string // command, key, channel, auth
+	Context    string // toplevel, multi
+	Object     string
+	Username   string
+	ClientInfo string
+	EntryID    int64
+	Created    time.Time
+	Updated    time.Time
+}
+
+// now는 테스트에서 시간을 바꿀 수 있도록 둔 시계입니다
+var now = time.Now
+
+// AddLogEntry는 거부된 명령어나 실패한 AUTH를 ACL LOG에 남깁니다
+func (a *ACL) AddLogEntry(reason, context, object, username, clientInfo string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t := now()
+	for _, entry := range a.log {
+		if entry.Reason == reason && entry.Context == context && entry.Object == object &&
+			entry.Username == username && t.Sub(entry.Updated) < logGroupingWindow {
+			entry.Count++
+			entry.Updated = t
+			entry.ClientInfo = clientInfo
+			return
+		}
+	}
+
+	entry := &LogEntry{
+		Count:      1,
+		Reason:     reason,
+		Context:    context,
+		Object:     object,
+		Username:   username,
+		ClientInfo: clientInfo,
+		EntryID:    a.nextLogID,
+		Created:    t,
+		Updated:    t,
+	}
+	a.nextLogID++
+
+	a.log = append([]*LogEntry{entry}, a.log...)
+	if len(a.log) > a.logMaxLen {
+		a.log = a.log[:a.logMaxLen]
+	}
+}
+
+// Log는 최신 항목부터 최대 count개(음수면 전부)의 복사본을 반환합니다
+func (a *ACL) Log(count int) []LogEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if count < 0 || count > len(a.log) {
+		count = len(a.log)
+	}
+	entries := make([]LogEntry, count)
+	for i := range entries {
+		entries[i] = *a.log[i]
+	}
+	return entries
+}
+
+func (a *ACL) ResetLog() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.log = nil
+}
+
+// SetLogMaxLen은 ACL LOG에 남길 최대 항목 수(acllog-max-len)를 바꿉니다
+func (a *ACL) SetLogMaxLen(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.logMaxLen = n
+	if len(a.log) > n {
+		a.log = a.log[:n]
+	}
+}
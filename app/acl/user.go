@@ -0,0 +1,344 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/glob"
+)
+
+// CommandTable은 ACL 규칙을 해석할 때 필요한 명령어 정보입니다
+type CommandTable interface {
+	HasCommand(name string) bool
+	CommandsInCategory(category string) []string
+	Categories() []string
+}
+
+var (
+	errSyntax          = errors.New("Syntax error")
+	errUnknownCommand  = errors.New("Unknown command or category name in ACL")
+	errBadPasswordHash = errors.New("The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters")
+	errNoSuchPassword  = errors.New("The password you are trying to remove from the user does not exist")
+	errKeyAfterAll     = errors.New("Adding a pattern after the * pattern (or the 'allkeys' flag) is not valid and does not have any effect. Try 'resetkeys' to start with an empty list of patterns")
+	errChannelAfterAll = errors.New("Adding a pattern after the * pattern (or the 'allchannels' flag) is not valid and does not have any effect. Try 'resetchannels' to start with an empty list of channels")
+)
+
+// User는 ACL 사용자 하나입니다
+// 필드는 ACL의 mu로 보호하며, 연결은 포인터를 들고 있으므로 규칙이 바뀌면 제자리에서 고칩니다
+type User struct {
+	name      string
+	enabled   bool
+	nopass    bool
+	passwords []string // SHA-256 hex
+
+	allCommands  bool
+	commands     map[string]bool // "get", "acl|setuser" 별로 허용/거부를 명시한 값
+	commandRules []string        // allCommands 기준 위에 적용한 규칙 (ACL LIST에 그대로 보여 줍니다)
+
+	allKeys bool
+	keys    []string
+
+	allChannels bool
+	channels    []string
+}
+
+func newUser(name string) *User {
+	return &User{
+		name:     name,
+		commands: make(map[string]bool),
+	}
+}
+
+func (u *User) Name() string {
+	return u.name
+}
+
+func (u *User) clone() *User {
+	c := *u
+	c.passwords = slices.Clone(u.passwords)
+	c.commands = make(map[string]bool, len(u.commands))
+	for name, allowed := range u.commands {
+		c.commands[name] = allowed
+	}
+	c.commandRules = slices.Clone(u.commandRules)
+	c.keys = slices.Clone(u.keys)
+	c.channels = slices.Clone(u.channels)
+	return &c
+}
+
+// setRule은 ACL SETUSER 규칙 하나를 적용합니다
+func (u *User) setRule(rule string, table CommandTable) error {
+	lower := strings.ToLower(rule)
+
+	switch lower {
+	case "on":
+		u.enabled = true
+		return nil
+	case "off":
+		u.enabled = false
+		return nil
+	case "nopass":
+		u.nopass = true
+		u.passwords = nil
+		return nil
+	case "resetpass":
+		u.nopass = false
+		u.passwords = nil
+		return nil
+	case "allkeys":
+		return u.setRule("~*", table)
+	case "resetkeys":
+		u.allKeys = false
+		u.keys = nil
+		return nil
+	case "allchannels":
+		return u.setRule("&*", table)
+	case "resetchannels":
+		u.allChannels = false
+		u.channels = nil
+		return nil
+	case "allcommands", "+@all":
+		u.allCommands = true
+		clear(u.commands)
+		u.commandRules = nil
+		return nil
+	case "nocommands", "-@all":
+		u.allCommands = false
+		clear(u.commands)
+		u.commandRules = nil
+		return nil
+	case "reset":
+		for _, r := range []string{"resetpass", "resetkeys", "resetchannels", "off", "-@all"} {
+			_ = u.setRule(r, table)
+		}
+		return nil
+	}
+
+	if rule == "" {
+		return errSyntax
+	}
+
+	switch rule[0] {
+	case '>':
+		u.addPassword(hashPassword(rule[1:]))
+		return nil
+	case '#':
+		hash := rule[1:]
+		if !isPasswordHash(hash) {
+			return errBadPasswordHash
+		}
+		u.addPassword(hash)
+		return nil
+	case '<':
+		return u.removePassword(hashPassword(rule[1:]))
+	case '!':
+		hash := rule[1:]
+		if !isPasswordHash(hash) {
+			return errBadPasswordHash
+		}
+		return u.removePassword(hash)
+	case '~':
+		if u.allKeys {
+			return errKeyAfterAll
+		}
+		if rule == "~*" {
+			u.allKeys = true
+			u.keys = nil
+		} else if !slices.Contains(u.keys, rule[1:]) {
+			u.keys = append(u.keys, rule[1:])
+		}
+		return nil
+	case '&':
+		if u.allChannels {
+			return errChannelAfterAll
+		}
+		if rule == "&*" {
+			u.allChannels = true
+			u.channels = nil
+		} else if !slices.Contains(u.channels, rule[1:]) {
+			u.channels = append(u.channels, rule[1:])
+		}
+		return nil
+	case '+', '-':
+		return u.setCommandRule(lower, table)
+	}
+
+	return errSyntax
+}
+
+// setCommandRule은 +cmd, -cmd, +@category, -@category 규칙을 적용합니다
+func (u *User) setCommandRule(rule string, table CommandTable) error {
+	allow := rule[0] == '+'
+	name := rule[1:]
+
+	if category, ok := strings.CutPrefix(name, "@"); ok {
+		if !slices.Contains(table.Categories(), category) {
+			return errUnknownCommand
+		}
+		for _, command := range table.CommandsInCategory(category) {
+			u.commands[command] = allow
+		}
+	} else {
+		if !table.HasCommand(name) {
+			return errUnknownCommand
+		}
+		if !strings.Contains(name, "|") {
+			// 부모 명령어 규칙은 그 아래 서브커맨드에 대한 이전 규칙을 덮어씁니다
+			for command := range u.commands {
+				if strings.HasPrefix(command, name+"|") {
+					delete(u.commands, command)
+				}
+			}
+		}
+		u.commands[name] = allow
+	}
+
+	u.commandRules = append(u.commandRules, rule)
+	return nil
+}
+
+func (u *User) addPassword(hash string) {
+	u.nopass = false
+	if !slices.Contains(u.passwords, hash) {
+		u.passwords = append(u.passwords, hash)
+	}
+}
+
+func (u *User) removePassword(hash string) error {
+	idx := slices.Index(u.passwords, hash)
+	if idx < 0 {
+		return errNoSuchPassword
+	}
+	u.passwords = slices.Delete(u.passwords, idx, idx+1)
+	return nil
+}
+
+// checkPassword는 비밀번호가 맞는지 확인합니다 (nopass면 항상 성공)
+func (u *User) checkPassword(password string) bool {
+	if u.nopass {
+		return true
+	}
+	return slices.Contains(u.passwords, hashPassword(password))
+}
+
+// canRun은 명령어 실행 권한이 있는지 확인합니다
+// 서브커맨드는 명시된 규칙, 부모 명령어 규칙, 전체 허용 여부 순서로 봅니다
+func (u *User) canRun(name string) bool {
+	if allowed, ok := u.commands[name]; ok {
+		return allowed
+	}
+	if parent, _, found := strings.Cut(name, "|"); found {
+		if allowed, ok := u.commands[parent]; ok {
+			return allowed
+		}
+	}
+	return u.allCommands
+}
+
+func (u *User) canAccessKey(key []byte) bool {
+	if u.allKeys {
+		return true
+	}
+	for _, pattern := range u.keys {
+		if glob.Match(pattern, string(key), false) {
+			return true
+		}
+	}
+	return false
+}
+
+// canAccessChannel은 채널 접근 권한을 확인합니다
+// PSUBSCRIBE처럼 패턴 자체를 구독하는 경우에는 허용된 패턴과 글자 그대로 같아야 합니다
+func (u *User) canAccessChannel(channel string, isPattern bool) bool {
+	if u.allChannels {
+		return true
+	}
+	for _, pattern := range u.channels {
+		if isPattern && pattern == channel {
+			return true
+		}
+		if !isPattern && glob.Match(pattern, channel, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// flags는 ACL GETUSER의 flags 항목입니다
+func (u *User) flags() []string {
+	flags := []string{"off"}
+	if u.enabled {
+		flags[0] = "on"
+	}
+	if u.nopass {
+		flags = append(flags, "nopass")
+	}
+	return flags
+}
+
+func (u *User) describeKeys() string {
+	if u.allKeys {
+		return "~*"
+	}
+	parts := make([]string, len(u.keys))
+	for i, pattern := range u.keys {
+		parts[i] = "~" + pattern
+	}
+	return strings.Join(parts, " ")
+}
+
+func (u *User) describeChannels() string {
+	if u.allChannels {
+		return "&*"
+	}
+	if len(u.channels) == 0 {
+		return "resetchannels"
+	}
+	parts := make([]string, len(u.channels))
+	for i, pattern := range u.channels {
+		parts[i] = "&" + pattern
+	}
+	return strings.Join(parts, " ")
+}
+
+func (u *User) describeCommands() string {
+	base := "-@all"
+	if u.allCommands {
+		base = "+@all"
+	}
+	return strings.Join(append([]string{base}, u.commandRules...), " ")
+}
+
+// describe는 ACL LIST와 aclfile에 쓰는 "user <name> <rules...>" 한 줄을 만듭니다
+func (u *User) describe() string {
+	parts := []string{"user", u.name}
+	parts = append(parts, u.flags()...)
+	for _, hash := range u.passwords {
+		parts = append(parts, "#"+hash)
+	}
+	if keys := u.describeKeys(); keys != "" {
+		parts = append(parts, keys)
+	}
+	parts = append(parts, u.describeChannels(), u.describeCommands())
+	return strings.Join(parts, " ")
+}
+
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+func isPasswordHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range hash {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,12 +2,14 @@ package client
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"strconv"
 	"strings"
 
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/protocol"
 	"github.com/codecrafters-io/redis-starter-go/app/types"
 )
@@ -16,10 +18,24 @@ type Client struct {
 	conn   net.Conn
 	reader *bufio.Reader
 	info   *types.ServerInfo
+	config *config.Config
 }
 
-func NewClient(info *types.ServerInfo) (*Client, error) {
-	conn, err := net.Dial("tcp", info.GetMasterAddress())
+// NewClient는 마스터에 접속합니다. tls-replication이 켜져 있으면 TLS로 연결합니다
+func NewClient(info *types.ServerInfo, cfg *config.Config) (*Client, error) {
+	var conn net.Conn
+	var err error
+	if cfg.TLSReplication {
+		host, _, _ := net.SplitHostPort(info.GetMasterAddress())
+		var tlsConfig *tls.Config
+		tlsConfig, err = cfg.ClientTLSConfig(host)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = tls.Dial("tcp", info.GetMasterAddress(), tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", info.GetMasterAddress())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -27,6 +43,7 @@ func NewClient(info *types.ServerInfo) (*Client, error) {
 		conn:   conn,
 		reader: bufio.NewReader(conn),
 		info:   info,
+		config: cfg,
 	}, nil
 }
 
@@ -34,6 +51,12 @@ func (c *Client) GetConn() net.Conn {
 	return c.conn
 }
 
+// GetReader는 핸드셰이크에 사용한 버퍼 리더를 반환합니다
+// RDB 뒤에 이어서 도착한 복제 스트림이 이미 버퍼에 들어 있을 수 있으므로 이 리더로 계속 읽어야 합니다
+func (c *Client) GetReader() io.Reader {
+	return c.reader
+}
+
 func (c *Client) CloseClient() {
 	_ = c.conn.Close()
 }
@@ -55,7 +78,14 @@ func (c *Client) Init() error {
 	}
 	fmt.Println("receive " + string(receive.Raw))
 
-	// 2) REPLCONF listening-port
+	// 2) AUTH (masterauth가 설정된 경우)
+	if err := c.auth(); err != nil {
+		fmt.Println("handshake failed")
+		fmt.Println(err.Error())
+		return fmt.Errorf("auth failed")
+	}
+
+	// 3) REPLCONF listening-port
 	msg = protocol.AppendArray([]byte{}, 3)
 	msg = protocol.AppendBulkString(msg, []byte("REPLCONF"))
 	msg = protocol.AppendBulkString(msg, []byte("listening-port"))
@@ -72,7 +102,7 @@ func (c *Client) Init() error {
 	}
 	fmt.Println("receive " + string(receive.Raw))
 
-	// 3) REPLCONF capa psync2
+	// 4) REPLCONF capa psync2
 	msg = protocol.AppendArray([]byte{}, 3)
 	msg = protocol.AppendBulkString(msg, []byte("REPLCONF"))
 	msg = protocol.AppendBulkString(msg, []byte("capa"))
@@ -89,7 +119,7 @@ func (c *Client) Init() error {
 	}
 	fmt.Println("receive " + string(receive.Raw))
 
-	// 4) PSYNC ? -1
+	// 5) PSYNC ? -1
 	msg = protocol.AppendArray([]byte{}, 3)
 	msg = protocol.AppendBulkString(msg, []byte("PSYNC"))
 	msg = protocol.AppendBulkString(msg, []byte("?"))
@@ -107,6 +137,26 @@ func (c *Client) Init() error {
 	return nil
 }
 
+// auth는 masterauth로 마스터에 인증합니다
+func (c *Client) auth() error {
+	if c.config.MasterAuth == "" {
+		return nil
+	}
+
+	msg := protocol.AppendArray([]byte{}, 2)
+	msg = protocol.AppendBulkString(msg, []byte("AUTH"))
+	msg = protocol.AppendBulkString(msg, []byte(c.config.MasterAuth))
+
+	receive, err := c.sendAndReceive(msg)
+	if err != nil {
+		return err
+	}
+	if receive.Type == protocol.Error {
+		return fmt.Errorf("unable to AUTH to MASTER: %s", string(receive.Data))
+	}
+	return nil
+}
+
 func (c *Client) sendAndReceive(msg []byte) (protocol.Resp, error) {
 	_, err := c.conn.Write(msg)
 	if err != nil {
@@ -142,13 +192,12 @@ func ReadRDB(reader *bufio.Reader) ([]byte, error) {
 		return nil, fmt.Errorf("null bulk string (no RDB)")
 	}
 
-	// 3. 본문 + CRLF 읽기
-	buf := make([]byte, length+2)
+	// 3. 본문 읽기 (RDB 전송은 일반 bulk string과 달리 끝에 CRLF가 없습니다)
+	buf := make([]byte, length)
 	_, err = io.ReadFull(reader, buf)
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. RDB payload만 반환
-	return buf[:length], nil
+	return buf, nil
 }
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerACLCommands() {
+	cm.register("ACL", cm.handleACL)
+}
+
+// handleACL은 ACL 서브커맨드를 나눠서 처리합니다
+func (cm *CommandManger) handleACL(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'acl' command"))
+		return
+	}
+
+	sub := strings.ToUpper(string(e.Args[0]))
+	args := e.Args[1:]
+
+	switch sub {
+	case "SETUSER":
+		cm.handleACLSetUser(e, args)
+	case "GETUSER":
+		cm.handleACLGetUser(e, args)
+	case "DELUSER":
+		cm.handleACLDelUser(e, args)
+	case "LIST":
+		cm.handleACLList(e, args)
+	case "USERS":
+		cm.handleACLUsers(e, args)
+	case "WHOAMI":
+		cm.handleACLWhoAmI(e, args)
+	case "CAT":
+		cm.handleACLCat(e, args)
+	case "LOG":
+		cm.handleACLLog(e, args)
+	case "LOAD":
+		cm.handleACLLoad(e, args)
+	case "SAVE":
+		cm.handleACLSave(e, args)
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try ACL HELP."))
+	}
+}
+
+func aclArgumentError(sub string) []byte {
+	return protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'acl|"+strings.ToLower(sub)+"' command")
+}
+
+func appendBulkStrings(buf []byte, values []string) []byte {
+	buf = protocol.AppendArray(buf, len(values))
+	for _, value := range values {
+		buf = protocol.AppendBulkString(buf, []byte(value))
+	}
+	return buf
+}
+
+// handleACLSetUser는 ACL SETUSER username [rule ...]을 처리합니다
+func (cm *CommandManger) handleACLSetUser(e types.CommandEvent, args [][]byte) {
+	if len(args) < 1 {
+		e.Ctx.Write(aclArgumentError("SETUSER"))
+		return
+	}
+
+	rules := make([]string, len(args)-1)
+	for i, rule := range args[1:] {
+		rules[i] = string(rule)
+	}
+
+	if err := cm.acl.SetUser(string(args[0]), rules); err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+// handleACLGetUser는 ACL GETUSER username을 처리합니다
+func (cm *CommandManger) handleACLGetUser(e types.CommandEvent, args [][]byte) {
+	if len(args) != 1 {
+		e.Ctx.Write(aclArgumentError("GETUSER"))
+		return
+	}
+
+	info, ok := cm.acl.GetUser(string(args[0]))
+	if !ok {
+		e.Ctx.Write(protocol.AppendNilBulkString())
+		return
+	}
+
+	msg := protocol.AppendArray([]byte{}, 12)
+	msg = protocol.AppendBulkString(msg, []byte("flags"))
+	msg = appendBulkStrings(msg, info.Flags)
+	msg = protocol.AppendBulkString(msg, []byte("passwords"))
+	msg = appendBulkStrings(msg, info.Passwords)
+	msg = protocol.AppendBulkString(msg, []byte("commands"))
+	msg = protocol.AppendBulkString(msg, []byte(info.Commands))
+	msg = protocol.AppendBulkString(msg, []byte("keys"))
+	msg = protocol.AppendBulkString(msg, []byte(info.Keys))
+	msg = protocol.AppendBulkString(msg, []byte("channels"))
+	msg = protocol.AppendBulkString(msg, []byte(info.Channels))
+	msg = protocol.AppendBulkString(msg, []byte("selectors"))
+	msg = protocol.AppendArray(msg, 0)
+	e.Ctx.Write(msg)
+}
+
+// handleACLDelUser는 ACL DELUSER username [username ...]을 처리합니다
+func (cm *CommandManger) handleACLDelUser(e types.CommandEvent, args [][]byte) {
+	if len(args) < 1 {
+		e.Ctx.Write(aclArgumentError("DELUSER"))
+		return
+	}
+
+	names := make([]string, len(args))
+	for i, name := range args {
+		names[i] = string(name)
+	}
+
+	deleted, err := cm.acl.DelUser(names)
+	if err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendInt([]byte{}, deleted))
+
+	// Redis와 같이 지운 사용자로 로그인해 있던 연결은 닫습니다
+	var targets []*types.ConnContext
+	for _, ctx := range cm.clients.List() {
+		if user := ctx.User(); user != nil && slices.Contains(names, user.Name()) {
+			targets = append(targets, ctx)
+		}
+	}
+	cm.killClients(targets, e.Ctx)
+}
+
+func (cm *CommandManger) handleACLList(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(aclArgumentError("LIST"))
+		return
+	}
+	e.Ctx.Write(appendBulkStrings([]byte{}, cm.acl.List()))
+}
+
+func (cm *CommandManger) handleACLUsers(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(aclArgumentError("USERS"))
+		return
+	}
+	e.Ctx.Write(appendBulkStrings([]byte{}, cm.acl.Usernames()))
+}
+
+func (cm *CommandManger) handleACLWhoAmI(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(aclArgumentError("WHOAMI"))
+		return
+	}
+	e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(e.Ctx.Username())))
+}
+
+// handleACLCat은 ACL CAT [category]를 처리합니다
+func (cm *CommandManger) handleACLCat(e types.CommandEvent, args [][]byte) {
+	switch len(args) {
+	case 0:
+		e.Ctx.Write(appendBulkStrings([]byte{}, cm.acl.Categories()))
+	case 1:
+		commands, ok := cm.acl.CommandsInCategory(string(args[0]))
+		if !ok {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR Unknown category '"+string(args[0])+"'"))
+			return
+		}
+		e.Ctx.Write(appendBulkStrings([]byte{}, commands))
+	default:
+		e.Ctx.Write(aclArgumentError("CAT"))
+	}
+}
+
+// handleACLLog는 ACL LOG [count | RESET]을 처리합니다
+func (cm *CommandManger) handleACLLog(e types.CommandEvent, args [][]byte) {
+	count := 10
+	switch len(args) {
+	case 0:
+	case 1:
+		if strings.EqualFold(string(args[0]), "RESET") {
+			cm.acl.ResetLog()
+			e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+			return
+		}
+		n, err := strconv.Atoi(string(args[0]))
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR value is not an integer or out of range"))
+			return
+		}
+		if n < 0 {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR value is out of range, must be positive"))
+			return
+		}
+		count = n
+	default:
+		e.Ctx.Write(aclArgumentError("LOG"))
+		return
+	}
+
+	entries := cm.acl.Log(count)
+	msg := protocol.AppendArray([]byte{}, len(entries))
+	for _, entry := range entries {
+		age := float64(time.Since(entry.Created).Milliseconds()) / 1000
+
+		msg = protocol.AppendArray(msg, 20)
+		msg = protocol.AppendBulkString(msg, []byte("count"))
+		msg = protocol.AppendInt(msg, entry.Count)
+		msg = protocol.AppendBulkString(msg, []byte("reason"))
+		msg = protocol.AppendBulkString(msg, []byte(entry.Reason))
+		msg = protocol.AppendBulkString(msg, []byte("context"))
+		msg = protocol.AppendBulkString(msg, []byte(entry.Context))
+		msg = protocol.AppendBulkString(msg, []byte("object"))
+		msg = protocol.AppendBulkString(msg, []byte(entry.Object))
+		msg = protocol.AppendBulkString(msg, []byte("username"))
+		msg = protocol.AppendBulkString(msg, []byte(entry.Username))
+		msg = protocol.AppendBulkString(msg, []byte("age-seconds"))
+		msg = protocol.AppendBulkString(msg, []byte(strconv.FormatFloat(age, 'f', 3, 64)))
+		msg = protocol.AppendBulkString(msg, []byte("client-info"))
+		msg = protocol.AppendBulkString(msg, []byte(entry.ClientInfo))
+		msg = protocol.AppendBulkString(msg, []byte("entry-id"))
+		msg = protocol.AppendInt(msg, int(entry.EntryID))
+		msg = protocol.AppendBulkString(msg, []byte("timestamp-created"))
+		msg = protocol.AppendInt(msg, int(entry.Created.UnixMilli()))
+		msg = protocol.AppendBulkString(msg, []byte("timestamp-last-updated"))
+		msg = protocol.AppendInt(msg, int(entry.Updated.UnixMilli()))
+	}
+	e.Ctx.Write(msg)
+}
+
+const errNoACLFile = "ERR This Redis instance is not configured to use an ACL file. You may want to specify users via the ACL SETUSER command and then issue a CONFIG REWRITE (assuming you have a Redis configuration file set) in order to store users in the Redis configuration."
+
+func (cm *CommandManger) handleACLLoad(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(aclArgumentError("LOAD"))
+		return
+	}
+	if cm.config.ACLFile == "" {
+		e.Ctx.Write(protocol.AppendError([]byte{}, errNoACLFile))
+		return
+	}
+	if err := cm.acl.LoadFile(cm.config.ACLFile); err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+func (cm *CommandManger) handleACLSave(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(aclArgumentError("SAVE"))
+		return
+	}
+	if cm.config.ACLFile == "" {
+		e.Ctx.Write(protocol.AppendError([]byte{}, errNoACLFile))
+		return
+	}
+	if err := cm.acl.SaveFile(cm.config.ACLFile); err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -9,6 +10,9 @@ import (
 	"github.com/codecrafters-io/redis-starter-go/app/types"
 )
 
+// errWrongArity를 Validate에서 돌려주면 다른 명령어와 같은 인수 개수 오류로 응답합니다
+var errWrongArity = errors.New("wrong number of arguments")
+
 type CommandArgs interface {
 	Validate() error
 }
@@ -131,6 +135,9 @@ func (ap *ArgParser) Parse(args CommandArgs) ParseResult {
 
 	// 커스텀 검증 실행
 	if err := args.Validate(); err != nil {
+		if errors.Is(err, errWrongArity) {
+			return ArgumentError(ap.event.Command)
+		}
 		return ValidationError(ap.event.Command, err.Error())
 	}
 
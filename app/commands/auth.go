@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerAuthCommands() {
+	cm.register("AUTH", cm.handleAuth)
+}
+
+// handleAuth는 AUTH [username] password 명령어를 처리합니다
+// 사용자 이름이 없으면 default 사용자로 인증합니다
+func (cm *CommandManger) handleAuth(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *AuthArgs) {
+		username, password := acl.DefaultUserName, args.First
+		if len(e.Args) == 2 {
+			username, password = args.First, args.Password
+		}
+
+		if len(e.Args) == 1 && cm.acl.DefaultUserNoPass() {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?"))
+			return
+		}
+
+		user, ok := cm.acl.Authenticate(username, password)
+		if !ok {
+			cm.acl.AddLogEntry("auth", "toplevel", "AUTH", username, e.Ctx.ClientInfo())
+			e.Ctx.Write(protocol.AppendError([]byte{}, "WRONGPASS invalid username-password pair or user is disabled."))
+			return
+		}
+
+		e.Ctx.SetUser(user, true)
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/protocol"
 	"github.com/codecrafters-io/redis-starter-go/app/types"
 )
@@ -19,7 +20,14 @@ func (cm *CommandManger) registerBasicCommands() {
 	cm.register("PSYNC", cm.handlePsync)
 }
 
+// handlePing은 구독 중인 연결에는 pub/sub 메시지와 구분되도록 ["pong", ""] 배열로 응답합니다
 func (cm *CommandManger) handlePing(e types.CommandEvent) {
+	if e.Ctx.Subscribed() {
+		msg := protocol.AppendArray([]byte{}, 2)
+		msg = protocol.AppendBulkString(msg, []byte("pong"))
+		e.Ctx.Write(protocol.AppendBulkString(msg, []byte{}))
+		return
+	}
 	e.Ctx.Write(protocol.AppendString([]byte{}, "PONG"))
 }
 
@@ -31,44 +39,122 @@ func (cm *CommandManger) handleEcho(e types.CommandEvent) {
 
 func (cm *CommandManger) handleType(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *TypeArgs) {
-		dataType := cm.store.Type(args.Key)
+		dataType := cm.db(e).Type(args.Key)
 		e.Ctx.Write(protocol.AppendString([]byte{}, dataType))
 	})
 }
 
 func (cm *CommandManger) handleInfo(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *InfoArgs) {
-		e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(cm.serverInfo.GetInfo())))
+		e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(cm.buildInfo(args.Section))))
 	})
 }
 
+// infoSection은 INFO 응답의 섹션 하나입니다
+type infoSection struct {
+	name string
+	body func() string
+}
+
+func (cm *CommandManger) infoSections() []infoSection {
+	return []infoSection{
+		{name: "Clients", body: cm.clientsInfo},
+		{name: "Memory", body: cm.memoryInfo},
+		{name: "Stats", body: cm.statsInfo},
+		{name: "Replication", body: cm.serverInfo.GetInfo},
+		{name: "Keyspace", body: cm.keyspaceInfo},
+	}
+}
+
+// statsInfo는 INFO stats 섹션입니다 (만료, 축출, lazyfree 통계는 Store가 모읍니다)
+func (cm *CommandManger) statsInfo() string {
+	expiredKeys, stalePerc := cm.dbs.ExpireStats()
+	_, _, evictedKeys := cm.dbs.MemoryStats()
+	_, lazyfreed := cm.dbs.LazyFreeStats()
+	channels, patterns := cm.pubsub.Stats()
+	return fmt.Sprintf("%s\r\nexpired_keys:%d\r\nexpired_stale_perc:%.2f\r\nevicted_keys:%d\r\npubsub_channels:%d\r\npubsub_patterns:%d\r\nlazyfreed_objects:%d",
+		cm.serverInfo.GetStats().Info(), expiredKeys, stalePerc, evictedKeys, channels, patterns, lazyfreed)
+}
+
+// memoryInfo는 INFO memory 섹션입니다 (used_memory는 키와 값의 메모리 사용량 어림값 합입니다)
+func (cm *CommandManger) memoryInfo() string {
+	used, peak, _ := cm.dbs.MemoryStats()
+	pending, _ := cm.dbs.LazyFreeStats()
+	cm.config.RLock()
+	maxMemory, policy := cm.config.MaxMemory, cm.config.MaxMemoryPolicy
+	cm.config.RUnlock()
+	return fmt.Sprintf("used_memory:%d\r\nused_memory_human:%s\r\nused_memory_peak:%d\r\nused_memory_peak_human:%s\r\n"+
+		"maxmemory:%d\r\nmaxmemory_human:%s\r\nmaxmemory_policy:%s\r\nlazyfree_pending_objects:%d",
+		used, bytesToHuman(used), peak, bytesToHuman(peak), maxMemory, bytesToHuman(maxMemory), policy, pending)
+}
+
+// clientsInfo는 INFO clients 섹션입니다 (Redis와 같이 connected_clients에서 레플리카는 뺍니다)
+func (cm *CommandManger) clientsInfo() string {
+	connected, blocked := 0, 0
+	for _, ctx := range cm.clients.List() {
+		if ctx.Type() == "replica" {
+			continue
+		}
+		connected++
+		if ctx.Blocked() {
+			blocked++
+		}
+	}
+	return fmt.Sprintf("connected_clients:%d\r\nblocked_clients:%d", connected, blocked)
+}
+
+// buildInfo는 요청한 섹션(없으면 전체)을 "# 섹션" 헤더와 함께 이어 붙입니다
+func (cm *CommandManger) buildInfo(section string) string {
+	section = strings.ToLower(section)
+	all := section == "" || section == "all" || section == "default" || section == "everything"
+
+	var sb strings.Builder
+	for _, s := range cm.infoSections() {
+		if !all && strings.ToLower(s.name) != section {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString("# " + s.name + "\r\n")
+		sb.WriteString(s.body())
+		sb.WriteString("\r\n")
+	}
+	return sb.String()
+}
+
 func (cm *CommandManger) handleReplConf(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *ReplConfArgs) {
 		switch strings.ToUpper(args.Reps2) {
 		case "LISTENING-PORT":
 			e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
-			if slices.Contains(cm.replicas, e.Ctx) {
-				return
-			}
-			cm.replicas = append(cm.replicas, e.Ctx)
+			cm.addReplica(e.Ctx)
 		case "CAPA":
 			e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
-			if slices.Contains(cm.replicas, e.Ctx) {
-				return
-			}
-			cm.replicas = append(cm.replicas, e.Ctx)
+			cm.addReplica(e.Ctx)
 		case "GETACK":
 			fmt.Println("GETACK")
 			msg := protocol.AppendArray([]byte{}, 3)
 			msg = protocol.AppendBulkString(msg, []byte("REPLCONF"))
 			msg = protocol.AppendBulkString(msg, []byte("ACK"))
 			msg = protocol.AppendBulkString(msg, []byte(strconv.Itoa(cm.serverInfo.GetOffset())))
-			e.Ctx.Write(msg)
+			e.Ctx.ForceWrite(msg)
 		}
 		return
 	})
 }
 
+// addReplica는 연결을 레플리카로 등록하고 replica 클래스의 출력 버퍼 제한을 적용합니다
+func (cm *CommandManger) addReplica(ctx *types.ConnContext) {
+	if slices.Contains(cm.replicas, ctx) {
+		return
+	}
+	ctx.SetClass(config.ClientReplica)
+	cm.replicas = append(cm.replicas, ctx)
+	// 새 레플리카는 어느 DB에서 시작하는지 모르므로 다음 전파 때 SELECT를 다시 보냅니다
+	cm.replDB = -1
+}
+
 func (cm *CommandManger) handlePsync(e types.CommandEvent) {
 	e.Ctx.Write(protocol.AppendString([]byte{}, "FULLRESYNC "+cm.serverInfo.GetReplId()+" 0"))
 
@@ -0,0 +1,425 @@
+package commands
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerClientCommands() {
+	cm.register("CLIENT", cm.handleClient)
+}
+
+// handleClient는 CLIENT 서브커맨드를 나눠서 처리합니다
+func (cm *CommandManger) handleClient(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'client' command"))
+		return
+	}
+
+	args := e.Args[1:]
+	switch strings.ToUpper(string(e.Args[0])) {
+	case "ID":
+		cm.handleClientID(e, args)
+	case "SETNAME":
+		cm.handleClientSetName(e, args)
+	case "GETNAME":
+		cm.handleClientGetName(e, args)
+	case "LIST":
+		cm.handleClientList(e, args)
+	case "INFO":
+		cm.handleClientInfo(e, args)
+	case "KILL":
+		cm.handleClientKill(e, args)
+	case "REPLY":
+		cm.handleClientReply(e, args)
+	case "PAUSE":
+		cm.handleClientPause(e, args)
+	case "UNPAUSE":
+		cm.handleClientUnpause(e, args)
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try CLIENT HELP."))
+	}
+}
+
+func clientArgumentError(sub string) []byte {
+	return protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'client|"+sub+"' command")
+}
+
+func syntaxError() []byte {
+	return protocol.AppendError([]byte{}, "ERR syntax error")
+}
+
+func (cm *CommandManger) handleClientID(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(clientArgumentError("id"))
+		return
+	}
+	e.Ctx.Write(protocol.AppendInt([]byte{}, int(e.Ctx.ID())))
+}
+
+// handleClientSetName은 CLIENT SETNAME name을 처리합니다 (빈 이름이면 이름을 지웁니다)
+func (cm *CommandManger) handleClientSetName(e types.CommandEvent, args [][]byte) {
+	if len(args) != 1 {
+		e.Ctx.Write(clientArgumentError("setname"))
+		return
+	}
+
+	// CLIENT LIST를 한 줄씩 나눠 읽을 수 있도록 공백과 제어 문자는 받지 않습니다
+	for _, c := range args[0] {
+		if c < '!' || c > '~' {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR Client names cannot contain spaces, newlines or special characters."))
+			return
+		}
+	}
+	e.Ctx.SetName(string(args[0]))
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+func (cm *CommandManger) handleClientGetName(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(clientArgumentError("getname"))
+		return
+	}
+	name := e.Ctx.Name()
+	if name == "" {
+		e.Ctx.Write(protocol.AppendNilBulkString())
+		return
+	}
+	e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(name)))
+}
+
+// handleClientList는 CLIENT LIST [TYPE type] [ID id [id ...]]을 처리합니다
+func (cm *CommandManger) handleClientList(e types.CommandEvent, args [][]byte) {
+	clients := cm.clients.List()
+
+	if len(args) > 0 {
+		switch strings.ToUpper(string(args[0])) {
+		case "TYPE":
+			if len(args) != 2 {
+				e.Ctx.Write(syntaxError())
+				return
+			}
+			clientType, ok := clientTypeByName(string(args[1]))
+			if !ok {
+				e.Ctx.Write(protocol.AppendError([]byte{}, "ERR Unknown client type '"+string(args[1])+"'"))
+				return
+			}
+			clients = slices.DeleteFunc(clients, func(ctx *types.ConnContext) bool { return ctx.Type() != clientType })
+		case "ID":
+			if len(args) < 2 {
+				e.Ctx.Write(syntaxError())
+				return
+			}
+			ids := make([]int64, len(args)-1)
+			for i, arg := range args[1:] {
+				id, err := strconv.ParseInt(string(arg), 10, 64)
+				if err != nil || id <= 0 {
+					e.Ctx.Write(protocol.AppendError([]byte{}, "ERR Invalid client ID"))
+					return
+				}
+				ids[i] = id
+			}
+			clients = slices.DeleteFunc(clients, func(ctx *types.ConnContext) bool { return !slices.Contains(ids, ctx.ID()) })
+		default:
+			e.Ctx.Write(syntaxError())
+			return
+		}
+	}
+
+	var sb strings.Builder
+	for _, ctx := range clients {
+		sb.WriteString(ctx.Info())
+		sb.WriteString("\n")
+	}
+	e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(sb.String())))
+}
+
+func (cm *CommandManger) handleClientInfo(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(clientArgumentError("info"))
+		return
+	}
+	e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(e.Ctx.Info()+"\n")))
+}
+
+// clientFilter는 CLIENT KILL의 필터 조건입니다 (비어 있는 조건은 검사하지 않습니다)
+type clientFilter struct {
+	id         int64
+	addr       string
+	laddr      string
+	user       string
+	clientType string
+	maxAge     time.Duration
+	skipMe     bool
+}
+
+func (f *clientFilter) match(ctx, self *types.ConnContext) bool {
+	if f.skipMe && ctx == self {
+		return false
+	}
+	if f.id != 0 && ctx.ID() != f.id {
+		return false
+	}
+	if f.addr != "" && ctx.Addr() != f.addr {
+		return false
+	}
+	if f.laddr != "" && ctx.Conn.LocalAddr().String() != f.laddr {
+		return false
+	}
+	if f.user != "" && ctx.Username() != f.user {
+		return false
+	}
+	if f.clientType != "" && ctx.Type() != f.clientType {
+		return false
+	}
+	if f.maxAge != 0 && ctx.Age() < f.maxAge {
+		return false
+	}
+	return true
+}
+
+// handleClientKill은 CLIENT KILL addr:port (예전 형식)과 CLIENT KILL <filter> <value> ... 를 처리합니다
+func (cm *CommandManger) handleClientKill(e types.CommandEvent, args [][]byte) {
+	if len(args) == 0 {
+		e.Ctx.Write(clientArgumentError("kill"))
+		return
+	}
+
+	// 예전 형식은 주소가 같은 클라이언트 하나를 닫고 OK로 응답합니다
+	if len(args) == 1 {
+		filter := &clientFilter{addr: string(args[0])}
+		targets := cm.matchClients(filter, e.Ctx)
+		if len(targets) == 0 {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR No such client"))
+			return
+		}
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.killClients(targets[:1], e.Ctx)
+		return
+	}
+
+	filter, errMsg := cm.parseClientFilter(args)
+	if errMsg != "" {
+		e.Ctx.Write(protocol.AppendError([]byte{}, errMsg))
+		return
+	}
+
+	targets := cm.matchClients(filter, e.Ctx)
+	e.Ctx.Write(protocol.AppendInt([]byte{}, len(targets)))
+	cm.killClients(targets, e.Ctx)
+}
+
+func (cm *CommandManger) parseClientFilter(args [][]byte) (*clientFilter, string) {
+	if len(args)%2 != 0 {
+		return nil, "ERR syntax error"
+	}
+
+	filter := &clientFilter{skipMe: true}
+	for i := 0; i < len(args); i += 2 {
+		value := string(args[i+1])
+		switch strings.ToUpper(string(args[i])) {
+		case "ID":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, "ERR client-id should be greater than 0"
+			}
+			filter.id = id
+		case "ADDR":
+			filter.addr = value
+		case "LADDR":
+			filter.laddr = value
+		case "USER":
+			if _, ok := cm.acl.GetUser(value); !ok {
+				return nil, "ERR No such user '" + value + "'"
+			}
+			filter.user = value
+		case "TYPE":
+			clientType, ok := clientTypeByName(value)
+			if !ok {
+				return nil, "ERR Unknown client type '" + value + "'"
+			}
+			filter.clientType = clientType
+		case "SKIPME":
+			switch strings.ToLower(value) {
+			case "yes":
+				filter.skipMe = true
+			case "no":
+				filter.skipMe = false
+			default:
+				return nil, "ERR syntax error"
+			}
+		case "MAXAGE":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || seconds <= 0 {
+				return nil, "ERR syntax error"
+			}
+			filter.maxAge = time.Duration(seconds) * time.Second
+		default:
+			return nil, "ERR syntax error"
+		}
+	}
+	return filter, ""
+}
+
+func (cm *CommandManger) matchClients(filter *clientFilter, self *types.ConnContext) []*types.ConnContext {
+	var targets []*types.ConnContext
+	for _, ctx := range cm.clients.List() {
+		if filter.match(ctx, self) {
+			targets = append(targets, ctx)
+		}
+	}
+	return targets
+}
+
+// killClients는 클라이언트 연결을 닫습니다
+// 명령어를 보낸 클라이언트 자신은 지금 쌓인 응답을 보낸 뒤에 닫습니다
+func (cm *CommandManger) killClients(targets []*types.ConnContext, self *types.ConnContext) {
+	for _, ctx := range targets {
+		if ctx == self {
+			ctx.CloseAfterReply()
+		} else {
+			ctx.Close()
+		}
+	}
+}
+
+// clientTypeByName은 CLIENT LIST/KILL의 TYPE 값을 ConnContext.Type 형식으로 바꿉니다 (slave는 replica의 별칭)
+func clientTypeByName(name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "normal", "master", "pubsub":
+		return strings.ToLower(name), true
+	case "replica", "slave":
+		return "replica", true
+	default:
+		return "", false
+	}
+}
+
+// handleClientReply는 CLIENT REPLY ON|OFF|SKIP을 처리합니다 (OFF와 SKIP에는 응답하지 않습니다)
+func (cm *CommandManger) handleClientReply(e types.CommandEvent, args [][]byte) {
+	if len(args) != 1 {
+		e.Ctx.Write(clientArgumentError("reply"))
+		return
+	}
+
+	switch strings.ToUpper(string(args[0])) {
+	case "ON":
+		e.Ctx.SetReplyMode(types.ReplyOn)
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+	case "OFF":
+		e.Ctx.SetReplyMode(types.ReplyOff)
+	case "SKIP":
+		e.Ctx.SetReplyMode(types.ReplySkip)
+	default:
+		e.Ctx.Write(syntaxError())
+	}
+}
+
+// handleClientPause는 CLIENT PAUSE timeout [WRITE|ALL]을 처리합니다
+func (cm *CommandManger) handleClientPause(e types.CommandEvent, args [][]byte) {
+	if len(args) != 1 && len(args) != 2 {
+		e.Ctx.Write(clientArgumentError("pause"))
+		return
+	}
+
+	ms, err := strconv.ParseInt(string(args[0]), 10, 64)
+	if err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR timeout is not an integer or out of range"))
+		return
+	}
+	if ms < 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR timeout is negative"))
+		return
+	}
+
+	all := true
+	if len(args) == 2 {
+		switch strings.ToUpper(string(args[1])) {
+		case "ALL":
+		case "WRITE":
+			all = false
+		default:
+			e.Ctx.Write(syntaxError())
+			return
+		}
+	}
+
+	cm.pauseClients(time.Now().Add(time.Duration(ms)*time.Millisecond), all)
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+func (cm *CommandManger) handleClientUnpause(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(clientArgumentError("unpause"))
+		return
+	}
+	cm.unpauseClients()
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+// pauseClients는 end까지 일반 클라이언트의 명령어(all이 false면 쓰기 명령어만)를 멈춥니다
+// 이미 멈춰 있으면 더 늦은 종료 시각과 더 넓은 범위를 따릅니다
+func (cm *CommandManger) pauseClients(end time.Time, all bool) {
+	if !cm.isPaused() {
+		cm.pauseAll = false
+	}
+	if end.After(cm.pauseEnd) {
+		cm.pauseEnd = end
+	}
+	cm.pauseAll = cm.pauseAll || all
+	cm.dbs.PauseExpiration(cm.pauseEnd)
+}
+
+func (cm *CommandManger) unpauseClients() {
+	cm.pauseEnd = time.Time{}
+	cm.pauseAll = false
+	cm.dbs.PauseExpiration(time.Time{})
+}
+
+func (cm *CommandManger) isPaused() bool {
+	return time.Now().Before(cm.pauseEnd)
+}
+
+// PauseRemaining은 CLIENT PAUSE가 끝날 때까지 남은 시간입니다 (멈춰 있지 않으면 0 이하)
+func (cm *CommandManger) PauseRemaining() time.Duration {
+	return time.Until(cm.pauseEnd)
+}
+
+// IsPaused는 CLIENT PAUSE 때문에 이 명령어를 지금 실행하지 말고 미뤄야 하는지 확인합니다
+// 복제 연결(마스터 링크와 레플리카)은 레플리카가 따라잡을 수 있도록 멈추지 않습니다
+func (cm *CommandManger) IsPaused(e types.CommandEvent) bool {
+	if !cm.isPaused() {
+		return false
+	}
+	switch e.Ctx.Type() {
+	case "master", "replica":
+		return false
+	}
+	if cm.pauseAll {
+		return true
+	}
+	return cm.isWriteCommand(e.Command, e.Args, e.Ctx)
+}
+
+// isWriteCommand는 데이터를 바꿀 수 있는 명령어인지 확인합니다
+// EXEC는 쌓아 둔 명령어 중 쓰기 명령어가 있으면 쓰기로 봅니다
+func (cm *CommandManger) isWriteCommand(command string, args [][]byte, ctx *types.ConnContext) bool {
+	if command == "EXEC" {
+		tx := ctx.GetTransaction()
+		if !tx.IsInTransaction() {
+			return false
+		}
+		for _, queued := range tx.GetCommands() {
+			if cm.isWriteCommand(queued.Name, queued.Args, ctx) {
+				return true
+			}
+		}
+		return false
+	}
+	return slices.Contains(cm.GetSpec(command, args).Categories, "write")
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerConfigCommands() {
+	cm.register("CONFIG", cm.handleConfig)
+}
+
+// handleConfig는 CONFIG 서브커맨드를 나눠서 처리합니다
+func (cm *CommandManger) handleConfig(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'config' command"))
+		return
+	}
+
+	args := e.Args[1:]
+	switch strings.ToUpper(string(e.Args[0])) {
+	case "GET":
+		cm.handleConfigGet(e, args)
+	case "SET":
+		cm.handleConfigSet(e, args)
+	case "REWRITE":
+		cm.handleConfigRewrite(e, args)
+	case "RESETSTAT":
+		cm.handleConfigResetStat(e, args)
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try CONFIG HELP."))
+	}
+}
+
+func configArgumentError(sub string) []byte {
+	return protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'config|"+sub+"' command")
+}
+
+// handleConfigGet은 CONFIG GET pattern [pattern ...]을 처리합니다
+func (cm *CommandManger) handleConfigGet(e types.CommandEvent, args [][]byte) {
+	if len(args) == 0 {
+		e.Ctx.Write(configArgumentError("get"))
+		return
+	}
+
+	patterns := make([]string, len(args))
+	for i, arg := range args {
+		patterns[i] = string(arg)
+	}
+
+	pairs := cm.config.Get(patterns...)
+	msg := protocol.AppendArray([]byte{}, len(pairs)*2)
+	for _, pair := range pairs {
+		msg = protocol.AppendBulkString(msg, []byte(pair[0]))
+		msg = protocol.AppendBulkString(msg, []byte(pair[1]))
+	}
+	e.Ctx.Write(msg)
+}
+
+// handleConfigSet은 CONFIG SET name value [name value ...]을 처리합니다
+func (cm *CommandManger) handleConfigSet(e types.CommandEvent, args [][]byte) {
+	if len(args) == 0 || len(args)%2 != 0 {
+		e.Ctx.Write(configArgumentError("set"))
+		return
+	}
+
+	pairs := make([][2]string, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		pairs = append(pairs, [2]string{string(args[i]), string(args[i+1])})
+	}
+
+	if err := cm.config.SetRuntime(pairs); err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+func (cm *CommandManger) handleConfigRewrite(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(configArgumentError("rewrite"))
+		return
+	}
+	if err := cm.config.Rewrite(); err != nil {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR "+err.Error()))
+		return
+	}
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
+
+func (cm *CommandManger) handleConfigResetStat(e types.CommandEvent, args [][]byte) {
+	if len(args) != 0 {
+		e.Ctx.Write(configArgumentError("resetstat"))
+		return
+	}
+	cm.serverInfo.GetStats().Reset()
+	cm.dbs.ResetStats()
+	e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerDBCommands() {
+	cm.register("SELECT", cm.handleSelect)
+	cm.register("MOVE", cm.handleMove)
+	cm.register("SWAPDB", cm.handleSwapDB)
+	cm.register("DBSIZE", cm.handleDBSize)
+	cm.register("FLUSHDB", cm.handleFlushDB)
+	cm.register("FLUSHALL", cm.handleFlushAll)
+}
+
+// dbIndex는 SELECT, MOVE, COPY DB 등의 데이터베이스 번호 인수를 검사합니다
+func (cm *CommandManger) dbIndex(arg string) (int, error) {
+	index, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("ERR value is not an integer or out of range")
+	}
+	if index < 0 || index >= cm.dbs.Len() {
+		return 0, fmt.Errorf("ERR DB index is out of range")
+	}
+	return index, nil
+}
+
+func (cm *CommandManger) handleSelect(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *SelectArgs) {
+		index, err := cm.dbIndex(args.Index)
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		e.Ctx.SelectDB(index)
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+	})
+}
+
+func (cm *CommandManger) handleMove(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *MoveArgs) {
+		dstDB, err := cm.dbIndex(args.DB)
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		moved, err := cm.dbs.Move(args.Key, e.Ctx.DB(), dstDB)
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		if !moved {
+			e.Ctx.Write(protocol.AppendInt([]byte{}, 0))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+		cm.Replicate(e)
+	})
+}
+
+func (cm *CommandManger) handleSwapDB(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *SwapDBArgs) {
+		indexes := [2]int{}
+		for i, arg := range []string{args.First, args.Second} {
+			index, err := cm.dbIndex(arg)
+			if err != nil {
+				// Redis와 같이 숫자가 아니면 몇 번째 인수인지 알려 줍니다
+				if _, convErr := strconv.Atoi(arg); convErr != nil {
+					err = fmt.Errorf("ERR invalid %s DB index", []string{"first", "second"}[i])
+				}
+				e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+				return
+			}
+			indexes[i] = index
+		}
+		cm.dbs.Swap(indexes[0], indexes[1])
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.Replicate(e)
+	})
+}
+
+func (cm *CommandManger) handleDBSize(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *DBSizeArgs) {
+		e.Ctx.Write(protocol.AppendInt([]byte{}, cm.db(e).Size()))
+	})
+}
+
+func (cm *CommandManger) handleFlushDB(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *FlushArgs) {
+		cm.dbs.Flush(e.Ctx.DB(), args.Async)
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.Replicate(e)
+	})
+}
+
+func (cm *CommandManger) handleFlushAll(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *FlushArgs) {
+		cm.dbs.FlushAll(args.Async)
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.Replicate(e)
+	})
+}
+
+// keyspaceInfo는 INFO keyspace 섹션입니다
+func (cm *CommandManger) keyspaceInfo() string {
+	return strings.Join(cm.dbs.KeyspaceInfo(), "\r\n")
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerExpireCommands() {
+	cm.register("EXPIRE", cm.expireHandler(time.Second, false))
+	cm.register("PEXPIRE", cm.expireHandler(time.Millisecond, false))
+	cm.register("EXPIREAT", cm.expireHandler(time.Second, true))
+	cm.register("PEXPIREAT", cm.expireHandler(time.Millisecond, true))
+	cm.register("TTL", cm.ttlHandler(time.Second, false))
+	cm.register("PTTL", cm.ttlHandler(time.Millisecond, false))
+	cm.register("EXPIRETIME", cm.ttlHandler(time.Second, true))
+	cm.register("PEXPIRETIME", cm.ttlHandler(time.Millisecond, true))
+	cm.register("PERSIST", cm.handlePersist)
+}
+
+// expireHandler는 EXPIRE 계열 명령어 핸들러를 만듭니다
+// unit은 인수의 단위이고, absolute면 인수를 unix 시각으로 봅니다
+// 레플리카에는 실행 시점과 상관없도록 PEXPIREAT으로 바꿔 전파합니다
+func (cm *CommandManger) expireHandler(unit time.Duration, absolute bool) func(e types.CommandEvent) {
+	return func(e types.CommandEvent) {
+		ParseAndExecute(e, func(args *ExpireArgs) {
+			at, ok := expireAt(args.Amount, unit, absolute)
+			if !ok {
+				e.Ctx.Write(protocol.AppendError([]byte{}, fmt.Sprintf("ERR invalid expire time in '%s' command", strings.ToLower(e.Command))))
+				return
+			}
+
+			if !cm.db(e).Expire(args.Key, at, args.Cond) {
+				e.Ctx.Write(protocol.AppendInt([]byte{}, 0))
+				return
+			}
+			e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+
+			if at.After(time.Now()) {
+				cm.propagate(e.Ctx.DB(), "PEXPIREAT", []byte(args.Key), []byte(strconv.FormatInt(at.UnixMilli(), 10)))
+			} else {
+				cm.propagate(e.Ctx.DB(), "DEL", []byte(args.Key))
+			}
+		})
+	}
+}
+
+// expireAt은 EXPIRE 계열 인수를 만료 시각으로 바꿉니다. 밀리초로 나타낼 수 없으면 false를 반환합니다
+func expireAt(amount int64, unit time.Duration, absolute bool) (time.Time, bool) {
+	scale := int64(unit / time.Millisecond)
+	if amount > math.MaxInt64/scale || amount < math.MinInt64/scale {
+		return time.Time{}, false
+	}
+	ms := amount * scale
+	if !absolute {
+		now := time.Now().UnixMilli()
+		if ms > math.MaxInt64-now {
+			return time.Time{}, false
+		}
+		ms += now
+	}
+	return time.UnixMilli(ms), true
+}
+
+// ttlHandler는 TTL, PTTL, EXPIRETIME, PEXPIRETIME 핸들러를 만듭니다
+// 키가 없으면 -2, 만료 시각이 없으면 -1을 반환합니다
+func (cm *CommandManger) ttlHandler(unit time.Duration, absolute bool) func(e types.CommandEvent) {
+	return func(e types.CommandEvent) {
+		ParseAndExecute(e, func(args *KeyArgs) {
+			at, exists := cm.db(e).ExpireTime(args.Key)
+			switch {
+			case !exists:
+				e.Ctx.Write(protocol.AppendInt([]byte{}, -2))
+			case at.IsZero():
+				e.Ctx.Write(protocol.AppendInt([]byte{}, -1))
+			case absolute && unit == time.Second:
+				e.Ctx.Write(protocol.AppendInt([]byte{}, int(at.Unix())))
+			case absolute:
+				e.Ctx.Write(protocol.AppendInt([]byte{}, int(at.UnixMilli())))
+			case unit == time.Second:
+				// Redis와 같이 남은 밀리초를 반올림해 초로 보여 줍니다
+				e.Ctx.Write(protocol.AppendInt([]byte{}, int((time.Until(at).Milliseconds()+500)/1000)))
+			default:
+				e.Ctx.Write(protocol.AppendInt([]byte{}, int(time.Until(at).Milliseconds())))
+			}
+		})
+	}
+}
+
+func (cm *CommandManger) handlePersist(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *KeyArgs) {
+		if !cm.db(e).Persist(args.Key) {
+			e.Ctx.Write(protocol.AppendInt([]byte{}, 0))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+		cm.Replicate(e)
+	})
+}
+
+// ActiveExpire는 이벤트 루프가 hz마다 호출하는 능동 만료 주기입니다
+// active-expire-effort가 클수록 한 번에 보는 키 수와 쓸 수 있는 CPU 시간이 늘어납니다
+// 지운 키는 레플리카에 DEL로 전파합니다
+func (cm *CommandManger) ActiveExpire() {
+	cm.config.RLock()
+	hz, effort := cm.config.Hz, cm.config.ActiveExpireEffort
+	cm.config.RUnlock()
+
+	samples := 20 + 5*(effort-1)
+	budget := time.Second * time.Duration(25+2*(effort-1)) / time.Duration(100*hz)
+	deleted := cm.dbs.ActiveExpireCycle(samples, budget)
+	for db, keys := range deleted {
+		for _, key := range keys {
+			cm.propagate(db, "DEL", []byte(key))
+		}
+	}
+	if len(deleted) > 0 {
+		cm.FlushReplicas()
+	}
+}
+
+// PropagateLazyExpired는 명령어가 접근하면서 지운 만료 키를 레플리카에 DEL로 전파합니다
+// 이벤트 루프가 명령어를 실행한 뒤에 호출하고, propagate도 명령어를 쓰기 전에 호출합니다
+func (cm *CommandManger) PropagateLazyExpired() {
+	for db, keys := range cm.dbs.LazyExpired() {
+		for _, key := range keys {
+			cm.feedReplicas(db, "DEL", []byte(key))
+		}
+	}
+}
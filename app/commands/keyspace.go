@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/store"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerKeyspaceCommands() {
+	cm.register("DEL", cm.handleDel)
+	cm.register("UNLINK", cm.handleDel)
+	cm.register("EXISTS", cm.handleExists)
+	cm.register("TOUCH", cm.handleTouch)
+	cm.register("RENAME", cm.handleRename)
+	cm.register("RENAMENX", cm.handleRenameNX)
+	cm.register("COPY", cm.handleCopy)
+	cm.register("DUMP", cm.handleDump)
+	cm.register("RESTORE", cm.handleRestore)
+	cm.register("KEYS", cm.handleKeys)
+	cm.register("SCAN", cm.handleScan)
+}
+
+// handleDel은 DEL, UNLINK 명령어를 처리합니다 (UNLINK는 큰 값을 백그라운드에서 해제합니다)
+func (cm *CommandManger) handleDel(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *KeysArgs) {
+		n := cm.db(e).Del(args.Keys, strings.EqualFold(e.Command, "UNLINK"))
+		e.Ctx.Write(protocol.AppendInt([]byte{}, n))
+		if n > 0 {
+			cm.Replicate(e)
+		}
+	})
+}
+
+func (cm *CommandManger) handleExists(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *KeysArgs) {
+		e.Ctx.Write(protocol.AppendInt([]byte{}, cm.db(e).Exists(args.Keys)))
+	})
+}
+
+func (cm *CommandManger) handleTouch(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *KeysArgs) {
+		e.Ctx.Write(protocol.AppendInt([]byte{}, cm.db(e).Touch(args.Keys)))
+	})
+}
+
+func (cm *CommandManger) handleRename(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *RenameArgs) {
+		if _, err := cm.db(e).Rename(args.Key, args.NewKey, false); err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.Replicate(e)
+	})
+}
+
+func (cm *CommandManger) handleRenameNX(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *RenameArgs) {
+		renamed, err := cm.db(e).Rename(args.Key, args.NewKey, true)
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		if !renamed {
+			e.Ctx.Write(protocol.AppendInt([]byte{}, 0))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+		cm.Replicate(e)
+	})
+}
+
+// handleCopy는 COPY 명령어를 처리합니다 (DB를 주지 않으면 지금 DB에 복사합니다)
+func (cm *CommandManger) handleCopy(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *CopyArgs) {
+		dstDB := e.Ctx.DB()
+		if args.DB != "" {
+			db, err := cm.dbIndex(args.DB)
+			if err != nil {
+				e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+				return
+			}
+			dstDB = db
+		}
+		copied, err := cm.dbs.Copy(e.Ctx.DB(), args.Source, dstDB, args.Destination, args.Replace)
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		if !copied {
+			e.Ctx.Write(protocol.AppendInt([]byte{}, 0))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+		cm.Replicate(e)
+	})
+}
+
+func (cm *CommandManger) handleKeys(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *PatternArgs) {
+		keys := cm.db(e).Keys(args.Pattern)
+		msg := protocol.AppendArray([]byte{}, len(keys))
+		for _, key := range keys {
+			msg = protocol.AppendBulkString(msg, []byte(key))
+		}
+		e.Ctx.Write(msg)
+	})
+}
+
+// handleScan은 SCAN 명령어를 처리합니다. 응답은 [다음 커서, 키 배열]입니다
+func (cm *CommandManger) handleScan(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *ScanArgs) {
+		cursor, keys := cm.db(e).Scan(args.CursorValue, args.Match, args.Count, args.Type)
+		msg := protocol.AppendArray([]byte{}, 2)
+		msg = protocol.AppendBulkString(msg, []byte(strconv.FormatUint(cursor, 10)))
+		msg = protocol.AppendArray(msg, len(keys))
+		for _, key := range keys {
+			msg = protocol.AppendBulkString(msg, []byte(key))
+		}
+		e.Ctx.Write(msg)
+	})
+}
+
+// handleDump는 키 값을 RESTORE로 되돌릴 수 있는 페이로드로 응답합니다 (키가 없으면 nil)
+func (cm *CommandManger) handleDump(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *KeyArgs) {
+		payload, ok := cm.db(e).Dump(args.Key)
+		if !ok {
+			e.Ctx.Write(protocol.AppendNilBulkString())
+			return
+		}
+		e.Ctx.Write(protocol.AppendBulkString([]byte{}, payload))
+	})
+}
+
+// handleRestore는 DUMP 페이로드를 키에 저장합니다
+// ttl은 밀리초이고 0이면 만료 시각이 없습니다. ABSTTL이면 unix 밀리초 시각입니다
+func (cm *CommandManger) handleRestore(e types.CommandEvent) {
+	ParseAndExecute(e, func(args *RestoreArgs) {
+		opts := store.RestoreOptions{Replace: args.Replace, Idle: time.Duration(args.Idle) * time.Second, Freq: args.Freq}
+		switch {
+		case args.TTLValue == 0:
+		case args.AbsTTL:
+			opts.ExpireAt = time.UnixMilli(args.TTLValue)
+		default:
+			opts.ExpireAt = time.Now().Add(time.Duration(args.TTLValue) * time.Millisecond)
+		}
+		if err := cm.db(e).Restore(args.Key, []byte(args.Payload), opts); err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
+			return
+		}
+		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
+		cm.Replicate(e)
+	})
+}
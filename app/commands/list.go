@@ -19,7 +19,7 @@ func (cm *CommandManger) registerListCommands() {
 // handleRPush는 RPUSH 명령어를 처리합니다
 func (cm *CommandManger) handleRPush(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *RPushArgs) {
-		length, ok := cm.store.RPush(args.Key, args.Values)
+		length, ok := cm.db(e).RPush(args.Key, args.Values)
 		if ok {
 			e.Ctx.Write(protocol.AppendInt([]byte{}, length))
 		} else {
@@ -31,7 +31,7 @@ func (cm *CommandManger) handleRPush(e types.CommandEvent) {
 // handleLPush는 LPUSH 명령어를 처리합니다
 func (cm *CommandManger) handleLPush(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *LPushArgs) {
-		length, ok := cm.store.LPush(args.Key, args.Values)
+		length, ok := cm.db(e).LPush(args.Key, args.Values)
 		if ok {
 			e.Ctx.Write(protocol.AppendInt([]byte{}, length))
 		} else {
@@ -43,7 +43,7 @@ func (cm *CommandManger) handleLPush(e types.CommandEvent) {
 // handleLRange는 LRANGE 명령어를 처리합니다
 func (cm *CommandManger) handleLRange(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *LRangeArgs) {
-		values, ok := cm.store.LRange(args.Key, args.Start, args.End)
+		values, ok := cm.db(e).LRange(args.Key, args.Start, args.End)
 		if !ok {
 			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong type"))
 			return
@@ -59,7 +59,7 @@ func (cm *CommandManger) handleLRange(e types.CommandEvent) {
 
 func (cm *CommandManger) handleLLen(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *LLenArgs) {
-		length, ok := cm.store.LLen(args.Key)
+		length, ok := cm.db(e).LLen(args.Key)
 
 		if ok {
 			e.Ctx.Write(protocol.AppendInt([]byte{}, length))
@@ -72,7 +72,7 @@ func (cm *CommandManger) handleLLen(e types.CommandEvent) {
 // handleLPop은 LPOP 명령어를 처리합니다
 func (cm *CommandManger) handleLPop(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *LPopArgs) {
-		data, ok := cm.store.LPop(args.Key, args.Count)
+		data, ok := cm.db(e).LPop(args.Key, args.Count)
 		if !ok {
 			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong type"))
 			return
@@ -97,8 +97,14 @@ func (cm *CommandManger) handleLPop(e types.CommandEvent) {
 
 func (cm *CommandManger) handleBLPop(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *BLPopArgs) {
+		db := cm.db(e)
 		go func() {
-			value, ok := cm.store.BLPop(args.Key, args.GetTimeoutDuration())
+			// 이벤트 루프 밖에서 응답하므로 직접 전송합니다
+			defer e.Ctx.Flush()
+			e.Ctx.SetBlocked(true)
+			defer e.Ctx.SetBlocked(false)
+
+			value, ok := db.BLPop(args.Key, args.GetTimeoutDuration())
 			if !ok {
 				e.Ctx.Write(protocol.AppendError([]byte{}, "ERR blpop failed"))
 				return
@@ -1,31 +1,51 @@
 package commands
 
 import (
-	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/app/store"
 	"github.com/codecrafters-io/redis-starter-go/app/types"
 )
 
 type ServerInfoProvider interface {
 	GetInfo() string
+	GetStats() *types.Stats
 	GetReplId() string
 	GetOffset() int
 }
 
 type CommandManger struct {
 	handlers   map[string]types.Handler
-	store      *store.Store
+	dbs        *store.Databases
 	serverInfo ServerInfoProvider
+	config     *config.Config
+	acl        *acl.ACL
+	clients    *types.ClientRegistry
+	pubsub     *pubsub.PubSub
 	replicas   []*types.ConnContext
+
+	// replDB는 복제 스트림에 마지막으로 보낸 SELECT 번호입니다 (-1이면 아직 보내지 않았습니다)
+	replDB int
+
+	// CLIENT PAUSE 상태 (이벤트 루프에서만 읽고 씁니다)
+	pauseEnd time.Time
+	pauseAll bool
 }
 
-func NewCommandManger(store *store.Store, serverInfo ServerInfoProvider) *CommandManger {
+func NewCommandManger(dbs *store.Databases, serverInfo ServerInfoProvider, cfg *config.Config) *CommandManger {
 	commandManger := &CommandManger{
 		handlers:   make(map[string]types.Handler),
-		store:      store,
+		dbs:        dbs,
+		replDB:     -1,
 		serverInfo: serverInfo,
+		config:     cfg,
+		clients:    types.NewClientRegistry(),
+		pubsub:     pubsub.New(),
 		replicas:   make([]*types.ConnContext, 0),
 	}
 	commandManger.registerBasicCommands()
@@ -33,10 +53,63 @@ func NewCommandManger(store *store.Store, serverInfo ServerInfoProvider) *Comman
 	commandManger.registerStreamCommands()
 	commandManger.registerTransactionCommands()
 	commandManger.registerListCommands()
+	commandManger.registerKeyspaceCommands()
+	commandManger.registerExpireCommands()
+	commandManger.registerDBCommands()
+	commandManger.registerObjectCommands()
+	commandManger.registerMemoryCommands()
+	commandManger.registerAuthCommands()
+	commandManger.registerACLCommands()
+	commandManger.registerConfigCommands()
+	commandManger.registerClientCommands()
+	commandManger.registerPubSubCommands()
+
+	// ACL 규칙은 등록된 명령어 표를 참고하므로 명령어를 모두 등록한 뒤에 만듭니다
+	commandManger.acl = acl.New(commandManger)
+	commandManger.acl.SetRequirePass(cfg.RequirePass)
+	commandManger.acl.SetLogMaxLen(cfg.ACLLogMaxLen)
+	cfg.OnChange("requirepass", func(c *config.Config) { commandManger.acl.SetRequirePass(c.RequirePass) })
+	cfg.OnChange("acllog-max-len", func(c *config.Config) { commandManger.acl.SetLogMaxLen(c.ACLLogMaxLen) })
+
+	commandManger.dbs.SetLazyFree(lazyFreePolicy(cfg))
+	for _, name := range []string{"lazyfree-lazy-eviction", "lazyfree-lazy-expire", "lazyfree-lazy-server-del", "lazyfree-lazy-user-del"} {
+		cfg.OnChange(name, func(c *config.Config) { commandManger.dbs.SetLazyFree(lazyFreePolicy(c)) })
+	}
+	commandManger.dbs.SetEviction(evictionPolicy(cfg))
+	for _, name := range []string{"maxmemory", "maxmemory-policy", "maxmemory-samples", "lfu-log-factor", "lfu-decay-time"} {
+		cfg.OnChange(name, func(c *config.Config) { commandManger.dbs.SetEviction(evictionPolicy(c)) })
+	}
+	commandManger.dbs.SetEncoding(encodingPolicy(cfg))
+	for _, name := range []string{"list-max-listpack-size", "stream-node-max-bytes", "stream-node-max-entries", "proto-max-bulk-len"} {
+		cfg.OnChange(name, func(c *config.Config) { commandManger.dbs.SetEncoding(encodingPolicy(c)) })
+	}
+	commandManger.dbs.SetPublisher(commandManger.pubsub.Publish)
+	commandManger.dbs.SetNotifyKeyspaceEvents(cfg.NotifyKeyspaceEvents)
+	cfg.OnChange("notify-keyspace-events", func(c *config.Config) { commandManger.dbs.SetNotifyKeyspaceEvents(c.NotifyKeyspaceEvents) })
 
 	return commandManger
 }
 
+// lazyFreePolicy는 lazyfree-lazy-* 설정을 Store에 넘길 형태로 바꿉니다 (설정 잠금을 잡은 상태로 호출)
+func lazyFreePolicy(c *config.Config) store.LazyFree {
+	return store.LazyFree{
+		Eviction:  c.LazyfreeLazyEviction,
+		Expire:    c.LazyfreeLazyExpire,
+		ServerDel: c.LazyfreeLazyServerDel,
+		UserDel:   c.LazyfreeLazyUserDel,
+	}
+}
+
+// ACL은 사용자와 권한 정보를 반환합니다
+func (cm *CommandManger) ACL() *acl.ACL {
+	return cm.acl
+}
+
+// Clients는 연결된 클라이언트 목록을 반환합니다
+func (cm *CommandManger) Clients() *types.ClientRegistry {
+	return cm.clients
+}
+
 func (cm *CommandManger) register(command string, handler types.Handler) {
 	cm.handlers[command] = handler
 }
@@ -46,15 +119,49 @@ func (cm *CommandManger) GetHandler(command string) (*types.Handler, bool) {
 	return &handler, exists
 }
 
+// db는 명령어를 보낸 연결이 SELECT한 데이터베이스입니다
+func (cm *CommandManger) db(e types.CommandEvent) *store.Store {
+	return cm.dbs.DB(e.Ctx.DB())
+}
+
+// Replicate는 명령어를 보낸 연결의 데이터베이스에서 실행한 것으로 레플리카에 전파합니다
 func (cm *CommandManger) Replicate(e types.CommandEvent) {
-	msg := protocol.AppendArray([]byte{}, len(e.Args)+1)
-	msg = protocol.AppendBulkString(msg, []byte(e.Command))
-	for _, arg := range e.Args {
+	cm.propagate(e.Ctx.DB(), e.Command, e.Args...)
+}
+
+// propagate는 db에서 실행할 명령어를 복제 스트림에 씁니다
+// 명령어가 접근하면서 지운 만료 키가 있으면 레플리카에서도 같은 순서로 지워지도록 DEL을 먼저 보냅니다
+func (cm *CommandManger) propagate(db int, command string, args ...[]byte) {
+	cm.PropagateLazyExpired()
+	cm.feedReplicas(db, command, args...)
+}
+
+// feedReplicas는 명령어를 레플리카 출력 버퍼에 씁니다. 데이터베이스가 바뀌면 먼저 SELECT를 보냅니다
+func (cm *CommandManger) feedReplicas(db int, command string, args ...[]byte) {
+	if len(cm.replicas) == 0 {
+		return
+	}
+	var msg []byte
+	if db != cm.replDB {
+		msg = protocol.AppendArray(msg, 2)
+		msg = protocol.AppendBulkString(msg, []byte("SELECT"))
+		msg = protocol.AppendBulkString(msg, []byte(strconv.Itoa(db)))
+		cm.replDB = db
+	}
+	msg = protocol.AppendArray(msg, len(args)+1)
+	msg = protocol.AppendBulkString(msg, []byte(command))
+	for _, arg := range args {
 		msg = protocol.AppendBulkString(msg, arg)
 	}
 	for _, replica := range cm.replicas {
 		replica.Write(msg)
 	}
-	fmt.Println(msg)
-	fmt.Println(string(msg))
+	// 실제 전송은 이벤트 루프가 묶음 단위로 FlushReplicas를 호출할 때 일어납니다
+}
+
+// FlushReplicas는 레플리카 출력 버퍼에 쌓인 복제 스트림을 전송합니다
+func (cm *CommandManger) FlushReplicas() {
+	for _, replica := range cm.replicas {
+		_ = replica.Flush()
+	}
 }
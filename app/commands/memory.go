@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/store"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerMemoryCommands() {
+	cm.register("MEMORY", cm.handleMemory)
+}
+
+var memoryHelp = []string{
+	"MEMORY <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"DOCTOR",
+	"    Return memory problems reports.",
+	"STATS",
+	"    Return information about the memory usage of the server.",
+	"USAGE <key> [SAMPLES <count>]",
+	"    Return memory in bytes used by <key> and its value. Nested values are",
+	"    sampled up to <count> times (default: 5, 0 means sample all).",
+	"HELP",
+	"    Print this help.",
+}
+
+// handleMemory는 MEMORY 서브커맨드를 나눠서 처리합니다
+func (cm *CommandManger) handleMemory(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'memory' command"))
+		return
+	}
+
+	args := e.Args[1:]
+	switch sub := strings.ToUpper(string(e.Args[0])); sub {
+	case "USAGE":
+		cm.handleMemoryUsage(e, args)
+	case "STATS", "DOCTOR", "HELP":
+		if len(args) != 0 {
+			e.Ctx.Write(subcommandArgumentError("memory", sub))
+			return
+		}
+		switch sub {
+		case "STATS":
+			e.Ctx.Write(cm.memoryStats())
+		case "DOCTOR":
+			e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(cm.memoryDoctor())))
+		default:
+			e.Ctx.Write(appendHelp(memoryHelp))
+		}
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try MEMORY HELP."))
+	}
+}
+
+// handleMemoryUsage는 MEMORY USAGE key [SAMPLES count]를 처리합니다 (키가 없으면 nil)
+func (cm *CommandManger) handleMemoryUsage(e types.CommandEvent, args [][]byte) {
+	if len(args) == 0 {
+		e.Ctx.Write(subcommandArgumentError("memory", "usage"))
+		return
+	}
+
+	samples := 5
+	for i := 1; i < len(args); i += 2 {
+		if !strings.EqualFold(string(args[i]), "SAMPLES") || i+1 >= len(args) {
+			e.Ctx.Write(syntaxError())
+			return
+		}
+		n, err := strconv.Atoi(string(args[i+1]))
+		if err != nil {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR value is not an integer or out of range"))
+			return
+		}
+		if n < 0 {
+			e.Ctx.Write(syntaxError())
+			return
+		}
+		samples = n
+	}
+
+	size, ok := cm.db(e).MemoryUsage(string(args[0]), samples)
+	if !ok {
+		e.Ctx.Write(protocol.AppendNilBulkString())
+		return
+	}
+	e.Ctx.Write(protocol.AppendInt([]byte{}, int(size)))
+}
+
+// memoryStats는 MEMORY STATS 응답입니다 (이름과 값이 번갈아 오는 배열, db.N은 중첩 배열)
+// total.allocated는 키와 값의 어림값 합이고, allocator.*는 Go 런타임 힙 통계입니다
+func (cm *CommandManger) memoryStats() []byte {
+	used, peak, _ := cm.dbs.MemoryStats()
+	pending, _ := cm.dbs.LazyFreeStats()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	dbs := cm.dbs.DBMemory()
+	keys, overhead := 0, int64(0)
+	for _, db := range dbs {
+		keys += db.Keys
+		overhead += db.Overhead
+	}
+	dataset := used - overhead
+	bytesPerKey, datasetPerc, peakPerc := int64(0), 0.0, 0.0
+	if keys > 0 {
+		bytesPerKey = used / int64(keys)
+	}
+	if used > 0 {
+		datasetPerc = float64(dataset) * 100 / float64(used)
+	}
+	if peak > 0 {
+		peakPerc = float64(used) * 100 / float64(peak)
+	}
+
+	n := 0
+	msg := []byte{}
+	field := func(name string, value []byte) {
+		msg = protocol.AppendBulkString(msg, []byte(name))
+		msg = append(msg, value...)
+		n++
+	}
+	intValue := func(v int64) []byte { return protocol.AppendInt([]byte{}, int(v)) }
+	floatValue := func(v float64) []byte {
+		return protocol.AppendBulkString([]byte{}, []byte(strconv.FormatFloat(v, 'f', 2, 64)))
+	}
+
+	field("peak.allocated", intValue(peak))
+	field("total.allocated", intValue(used))
+	field("overhead.total", intValue(overhead))
+	for _, db := range dbs {
+		value := protocol.AppendArray([]byte{}, 4)
+		value = protocol.AppendBulkString(value, []byte("overhead.hashtable.main"))
+		value = protocol.AppendInt(value, int(db.Overhead))
+		value = protocol.AppendBulkString(value, []byte("expires.count"))
+		value = protocol.AppendInt(value, db.Expires)
+		field("db."+strconv.Itoa(db.Index), value)
+	}
+	field("keys.count", intValue(int64(keys)))
+	field("keys.bytes-per-key", intValue(bytesPerKey))
+	field("dataset.bytes", intValue(dataset))
+	field("dataset.percentage", floatValue(datasetPerc))
+	field("peak.percentage", floatValue(peakPerc))
+	field("allocator.allocated", intValue(int64(ms.HeapAlloc)))
+	field("allocator.active", intValue(int64(ms.HeapInuse)))
+	field("allocator.resident", intValue(int64(ms.HeapSys)))
+	field("lazyfree.pending", intValue(pending))
+
+	return append(protocol.AppendArray([]byte{}, n*2), msg...)
+}
+
+// memoryDoctorMinUsage보다 적게 쓰는 인스턴스는 진단하지 않습니다 (Redis와 같이 5MB)
+const memoryDoctorMinUsage = 5 * 1024 * 1024
+
+// memoryDoctor는 MEMORY DOCTOR 보고서입니다
+func (cm *CommandManger) memoryDoctor() string {
+	used, peak, _ := cm.dbs.MemoryStats()
+	if used < memoryDoctorMinUsage {
+		return "Hi Sam, this instance is empty or is using very little memory, my issues detector can't be used in these conditions. " +
+			"Please, leave for your mission on Earth and fill it with some data. " +
+			"The new Sam and I will be back to our programming as soon as I finished rebooting.\n"
+	}
+
+	pending, _ := cm.dbs.LazyFreeStats()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	var issues []string
+	if peak > used*3/2 {
+		issues = append(issues, fmt.Sprintf("* Peak memory: In the past this instance used more than 150%% the memory that is currently using (peak %s, now %s). "+
+			"The Go runtime returns memory to the OS only gradually, so the process may stay large for a while after a peak.", bytesToHuman(peak), bytesToHuman(used)))
+	}
+	if int64(ms.HeapAlloc) > used*2 {
+		issues = append(issues, fmt.Sprintf("* Memory outside the dataset: The Go heap (%s) is more than twice the dataset estimate (%s). "+
+			"Large client output buffers, the replication backlog or values waiting for lazyfree may be holding memory.", bytesToHuman(int64(ms.HeapAlloc)), bytesToHuman(used)))
+	}
+	if pending > 0 {
+		issues = append(issues, fmt.Sprintf("* Lazyfree: %d values are still waiting to be freed in the background.", pending))
+	}
+
+	if len(issues) == 0 {
+		return "Hi Sam, I can't find any memory issue in your instance. I can only account for what occurs on this base.\n"
+	}
+	return "Sam, I detected a few issues in this Redis instance memory implants:\n\n" +
+		strings.Join(issues, "\n\n") + "\n\nI'm here to keep you safe, Sam. I want to help you.\n"
+}
+
+// evictionPolicy는 maxmemory 관련 설정을 Store에 넘길 형태로 바꿉니다 (설정 잠금을 잡은 상태로 호출)
+func evictionPolicy(c *config.Config) store.Eviction {
+	return store.Eviction{
+		MaxMemory:    c.MaxMemory,
+		Policy:       c.MaxMemoryPolicy,
+		Samples:      c.MaxMemorySamples,
+		LFULogFactor: c.LFULogFactor,
+		LFUDecayTime: c.LFUDecayTime,
+	}
+}
+
+// CheckMemory는 명령어를 실행하기 전에 maxmemory를 넘었으면 키를 쫓아내고, 쫓아낸 키는 레플리카에 DEL로 전파합니다
+// 그래도 넘치면 메모리를 늘릴 수 있는 명령어만 OOM 에러로 거부하고 false를 반환합니다 (DEL 같은 명령어는 계속 받습니다)
+func (cm *CommandManger) CheckMemory(e types.CommandEvent) bool {
+	evicted, ok := cm.dbs.Evict()
+	for db, keys := range evicted {
+		for _, key := range keys {
+			cm.propagate(db, "DEL", []byte(key))
+		}
+	}
+	if ok || !cm.isDenyOOMCommand(e.Command, e.Args, e.Ctx) {
+		return true
+	}
+	e.Ctx.Write(protocol.AppendError(nil, "OOM command not allowed when used memory > 'maxmemory'."))
+	return false
+}
+
+// isDenyOOMCommand는 maxmemory를 넘었을 때 거부할 명령어인지 확인합니다
+// EXEC는 쌓아 둔 명령어 중 거부할 명령어가 있으면 트랜잭션 전체를 거부합니다
+func (cm *CommandManger) isDenyOOMCommand(command string, args [][]byte, ctx *types.ConnContext) bool {
+	if command == "EXEC" {
+		tx := ctx.GetTransaction()
+		if !tx.IsInTransaction() {
+			return false
+		}
+		for _, queued := range tx.GetCommands() {
+			if cm.isDenyOOMCommand(queued.Name, queued.Args, ctx) {
+				return true
+			}
+		}
+		return false
+	}
+	return cm.GetSpec(command, args).DenyOOM
+}
+
+// bytesToHuman은 INFO의 *_human 필드처럼 바이트 수를 B, K, M, G 단위로 보여 줍니다
+func bytesToHuman(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.2fK", float64(n)/1024)
+	case n < 1024*1024*1024:
+		return fmt.Sprintf("%.2fM", float64(n)/(1024*1024))
+	default:
+		return fmt.Sprintf("%.2fG", float64(n)/(1024*1024*1024))
+	}
+}
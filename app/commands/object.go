@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/store"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerObjectCommands() {
+	cm.register("OBJECT", cm.handleObject)
+}
+
+// encodingPolicy는 자료구조 인코딩 기준 설정을 Store에 넘길 형태로 바꿉니다 (설정 잠금을 잡은 상태로 호출)
+func encodingPolicy(c *config.Config) store.Encoding {
+	return store.Encoding{
+		ListMaxListpackSize:  c.ListMaxListpackSize,
+		StreamNodeMaxBytes:   c.StreamNodeMaxBytes,
+		StreamNodeMaxEntries: c.StreamNodeMaxEntries,
+		ProtoMaxBulkLen:      c.ProtoMaxBulkLen,
+	}
+}
+
+var objectHelp = []string{
+	"OBJECT <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"ENCODING <key>",
+	"    Return the kind of internal representation used in order to store the value",
+	"    associated with a <key>.",
+	"FREQ <key>",
+	"    Return the access frequency index of the <key>. The returned integer is",
+	"    proportional to the logarithm of the recent access frequency of the key.",
+	"IDLETIME <key>",
+	"    Return the idle time of the <key>, that is the approximated number of",
+	"    seconds elapsed since the last access to the key.",
+	"REFCOUNT <key>",
+	"    Return the number of references of the value associated with the specified",
+	"    <key>.",
+	"HELP",
+	"    Print this help.",
+}
+
+// handleObject는 OBJECT 서브커맨드를 나눠서 처리합니다
+// 키를 들여다보기만 하므로 키의 접근 시각과 LFU 카운터는 바꾸지 않습니다
+func (cm *CommandManger) handleObject(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'object' command"))
+		return
+	}
+
+	sub := strings.ToUpper(string(e.Args[0]))
+	if sub == "HELP" {
+		e.Ctx.Write(appendHelp(objectHelp))
+		return
+	}
+	switch sub {
+	case "ENCODING", "IDLETIME", "FREQ", "REFCOUNT":
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try OBJECT HELP."))
+		return
+	}
+	if len(e.Args) != 2 {
+		e.Ctx.Write(subcommandArgumentError("object", sub))
+		return
+	}
+
+	info, ok := cm.db(e).Object(string(e.Args[1]))
+	if !ok {
+		e.Ctx.Write(protocol.AppendNilBulkString())
+		return
+	}
+	lfu := strings.HasSuffix(cm.evictionPolicyName(), "-lfu")
+	switch sub {
+	case "ENCODING":
+		e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(info.Encoding)))
+	case "IDLETIME":
+		if lfu {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR An LFU maxmemory policy is selected, idle time not tracked. Please note that when switching between policies at runtime LRU and LFU data will take some time to adjust."))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, int(info.Idle.Seconds())))
+	case "FREQ":
+		if !lfu {
+			e.Ctx.Write(protocol.AppendError([]byte{}, "ERR An LFU maxmemory policy is not selected, access frequency not tracked. Please note that when switching between policies at runtime LRU and LFU data will take some time to adjust."))
+			return
+		}
+		e.Ctx.Write(protocol.AppendInt([]byte{}, info.Freq))
+	case "REFCOUNT":
+		// 값을 키끼리 공유하지 않으므로 항상 1입니다
+		e.Ctx.Write(protocol.AppendInt([]byte{}, 1))
+	}
+}
+
+// evictionPolicyName은 지금 maxmemory-policy 값입니다
+func (cm *CommandManger) evictionPolicyName() string {
+	cm.config.RLock()
+	defer cm.config.RUnlock()
+	return cm.config.MaxMemoryPolicy
+}
+
+// subcommandArgumentError는 "object|encoding"처럼 서브커맨드 이름을 붙인 인수 개수 에러입니다
+func subcommandArgumentError(command, sub string) []byte {
+	return protocol.AppendError([]byte{}, "ERR wrong number of arguments for '"+command+"|"+strings.ToLower(sub)+"' command")
+}
+
+// appendHelp는 HELP 서브커맨드 응답을 만듭니다 (한 줄씩 simple string 배열)
+func appendHelp(lines []string) []byte {
+	msg := protocol.AppendArray([]byte{}, len(lines))
+	for _, line := range lines {
+		msg = protocol.AppendString(msg, line)
+	}
+	return msg
+}
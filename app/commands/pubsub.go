@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+	"github.com/codecrafters-io/redis-starter-go/app/types"
+)
+
+func (cm *CommandManger) registerPubSubCommands() {
+	cm.register("SUBSCRIBE", cm.handleSubscribe)
+	cm.register("UNSUBSCRIBE", cm.handleUnsubscribe)
+	cm.register("PSUBSCRIBE", cm.handlePSubscribe)
+	cm.register("PUNSUBSCRIBE", cm.handlePUnsubscribe)
+	cm.register("PUBLISH", cm.handlePublish)
+	cm.register("PUBSUB", cm.handlePubSub)
+}
+
+// subscribedModeCommands는 구독 중인 연결이 보낼 수 있는 명령어입니다
+var subscribedModeCommands = map[string]bool{
+	"SUBSCRIBE": true, "UNSUBSCRIBE": true, "PSUBSCRIBE": true, "PUNSUBSCRIBE": true, "PING": true, "QUIT": true, "RESET": true,
+}
+
+// CheckSubscribed는 구독 중인 연결이 pub/sub 모드에서 쓸 수 없는 명령어를 보냈으면 에러로 응답하고 false를 반환합니다
+func (cm *CommandManger) CheckSubscribed(e types.CommandEvent) bool {
+	if !e.Ctx.Subscribed() || subscribedModeCommands[e.Command] {
+		return true
+	}
+	e.Ctx.Write(protocol.AppendError(nil, "ERR Can't execute '"+strings.ToLower(e.Command)+
+		"': only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT / RESET are allowed in this context"))
+	return false
+}
+
+// UnsubscribeAll은 연결이 끊길 때 남은 구독을 모두 풉니다
+func (cm *CommandManger) UnsubscribeAll(ctx *types.ConnContext) {
+	cm.pubsub.UnsubscribeAll(ctx)
+}
+
+// checkChannels는 사용자가 channels(isPattern이면 패턴)에 접근할 수 있는지 확인합니다
+// 하나라도 거부되면 ACL LOG에 남기고 NOPERM 에러로 응답합니다
+func (cm *CommandManger) checkChannels(e types.CommandEvent, channels []string, isPattern bool) bool {
+	for _, channel := range channels {
+		denied := cm.acl.CheckChannel(e.Ctx.User(), channel, isPattern)
+		if denied == nil {
+			continue
+		}
+		context := "toplevel"
+		if e.Ctx.GetTransaction().IsInTransaction() {
+			context = "multi"
+		}
+		cm.acl.AddLogEntry(denied.Reason, context, denied.Object, e.Ctx.Username(), e.Ctx.ClientInfo())
+		e.Ctx.Write(protocol.AppendError(nil, denied.Error()))
+		return false
+	}
+	return true
+}
+
+func (cm *CommandManger) handleSubscribe(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'subscribe' command"))
+		return
+	}
+	channels := argStrings(e.Args)
+	if !cm.checkChannels(e, channels, false) {
+		return
+	}
+	e.Ctx.SetSubscriptions(cm.pubsub.Subscribe(e.Ctx, channels))
+}
+
+func (cm *CommandManger) handlePSubscribe(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'psubscribe' command"))
+		return
+	}
+	patterns := argStrings(e.Args)
+	if !cm.checkChannels(e, patterns, true) {
+		return
+	}
+	e.Ctx.SetSubscriptions(cm.pubsub.PSubscribe(e.Ctx, patterns))
+}
+
+// handleUnsubscribe는 인수가 없으면 모든 채널 구독을 풉니다
+func (cm *CommandManger) handleUnsubscribe(e types.CommandEvent) {
+	e.Ctx.SetSubscriptions(cm.pubsub.Unsubscribe(e.Ctx, argStrings(e.Args)))
+}
+
+func (cm *CommandManger) handlePUnsubscribe(e types.CommandEvent) {
+	e.Ctx.SetSubscriptions(cm.pubsub.PUnsubscribe(e.Ctx, argStrings(e.Args)))
+}
+
+// handlePublish는 메시지를 받은 구독자 수로 응답합니다
+// 레플리카의 구독자도 메시지를 받도록 복제 스트림에 전파합니다
+func (cm *CommandManger) handlePublish(e types.CommandEvent) {
+	if len(e.Args) != 2 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'publish' command"))
+		return
+	}
+	channel := string(e.Args[0])
+	if !cm.checkChannels(e, []string{channel}, false) {
+		return
+	}
+	n := cm.pubsub.Publish(channel, string(e.Args[1]))
+	cm.Replicate(e)
+	e.Ctx.Write(protocol.AppendInt([]byte{}, n))
+}
+
+var pubsubHelp = []string{
+	"PUBSUB <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"CHANNELS [<pattern>]",
+	"    Return the currently active channels matching a <pattern> (default: '*').",
+	"NUMPAT",
+	"    Return number of subscriptions to patterns.",
+	"NUMSUB [<channel> ...]",
+	"    Return the number of subscribers for the specified channels, excluding",
+	"    pattern subscriptions(default: no channels).",
+	"HELP",
+	"    Print this help.",
+}
+
+// handlePubSub은 PUBSUB 서브커맨드를 나눠서 처리합니다
+func (cm *CommandManger) handlePubSub(e types.CommandEvent) {
+	if len(e.Args) == 0 {
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR wrong number of arguments for 'pubsub' command"))
+		return
+	}
+
+	args := e.Args[1:]
+	switch sub := strings.ToUpper(string(e.Args[0])); sub {
+	case "CHANNELS":
+		if len(args) > 1 {
+			e.Ctx.Write(subcommandArgumentError("pubsub", sub))
+			return
+		}
+		pattern := ""
+		if len(args) == 1 {
+			pattern = string(args[0])
+		}
+		channels := cm.pubsub.Channels(pattern)
+		msg := protocol.AppendArray([]byte{}, len(channels))
+		for _, channel := range channels {
+			msg = protocol.AppendBulkString(msg, []byte(channel))
+		}
+		e.Ctx.Write(msg)
+	case "NUMSUB":
+		channels := argStrings(args)
+		counts := cm.pubsub.NumSub(channels)
+		msg := protocol.AppendArray([]byte{}, len(channels)*2)
+		for i, channel := range channels {
+			msg = protocol.AppendBulkString(msg, []byte(channel))
+			msg = protocol.AppendInt(msg, counts[i])
+		}
+		e.Ctx.Write(msg)
+	case "NUMPAT", "HELP":
+		if len(args) != 0 {
+			e.Ctx.Write(subcommandArgumentError("pubsub", sub))
+			return
+		}
+		if sub == "NUMPAT" {
+			e.Ctx.Write(protocol.AppendInt([]byte{}, cm.pubsub.NumPat()))
+		} else {
+			e.Ctx.Write(appendHelp(pubsubHelp))
+		}
+	default:
+		e.Ctx.Write(protocol.AppendError([]byte{}, "ERR unknown subcommand '"+string(e.Args[0])+"'. Try PUBSUB HELP."))
+	}
+}
+
+func argStrings(args [][]byte) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = string(arg)
+	}
+	return out
+}
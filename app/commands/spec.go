@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+)
+
+// ACL 카테고리 (ACL CAT으로 보여 주는 순서)
+var aclCategories = []string{
+	"keyspace", "read", "write", "set", "sortedset", "list", "hash", "string",
+	"bitmap", "hyperloglog", "geo", "stream", "pubsub", "admin", "fast", "slow",
+	"blocking", "dangerous", "connection", "transaction", "scripting",
+}
+
+// CommandSpec은 ACL 검사에 필요한 명령어 정보입니다
+// 키 위치는 Redis와 같이 명령어 이름을 0번으로 센 인덱스입니다
+type CommandSpec struct {
+	Name       string   // 소문자 전체 이름 (서브커맨드면 "acl|setuser")
+	Categories []string // @ 없이 적은 ACL 카테고리
+	FirstKey   int      // 첫 번째 키 위치, 0이면 키가 없습니다
+	LastKey    int      // 마지막 키 위치, 음수면 끝에서부터 셉니다
+	Step       int
+	NoAuth     bool // 인증 전에도 실행할 수 있는 명령어 (AUTH)
+	DenyOOM    bool // maxmemory를 넘었을 때 거부할 명령어 (메모리를 늘릴 수 있는 쓰기)
+
+	// KeysFunc는 키 위치가 인수 값에 따라 달라지는 명령어(XREAD 등)의 키를 찾습니다
+	KeysFunc func(args [][]byte) [][]byte
+}
+
+// commandSpecs는 명령어 이름(서브커맨드는 "ACL|SETUSER")별 정보입니다
+var commandSpecs = map[string]CommandSpec{
+	"PING":     {Categories: []string{"fast", "connection"}},
+	"ECHO":     {Categories: []string{"fast", "connection"}},
+	"TYPE":     {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"INFO":     {Categories: []string{"slow", "dangerous"}},
+	"REPLCONF": {Categories: []string{"admin", "slow", "dangerous"}},
+	"PSYNC":    {Categories: []string{"admin", "slow", "dangerous"}},
+	"AUTH":     {Categories: []string{"fast", "connection"}, NoAuth: true},
+
+	"DEL":      {Categories: []string{"keyspace", "write", "slow"}, FirstKey: 1, LastKey: -1, Step: 1},
+	"UNLINK":   {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: -1, Step: 1},
+	"EXISTS":   {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: -1, Step: 1},
+	"TOUCH":    {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: -1, Step: 1},
+	"RENAME":   {Categories: []string{"keyspace", "write", "slow"}, FirstKey: 1, LastKey: 2, Step: 1},
+	"RENAMENX": {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 2, Step: 1},
+	"COPY":     {Categories: []string{"keyspace", "write", "slow"}, FirstKey: 1, LastKey: 2, Step: 1, DenyOOM: true},
+	"DUMP":     {Categories: []string{"keyspace", "read", "slow"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"RESTORE":  {Categories: []string{"keyspace", "write", "slow", "dangerous"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+	"KEYS":     {Categories: []string{"keyspace", "read", "slow", "dangerous"}},
+	"SCAN":     {Categories: []string{"keyspace", "read", "slow"}},
+
+	"OBJECT|ENCODING": {Categories: []string{"keyspace", "read", "slow"}, FirstKey: 2, LastKey: 2, Step: 1},
+	"OBJECT|IDLETIME": {Categories: []string{"keyspace", "read", "slow"}, FirstKey: 2, LastKey: 2, Step: 1},
+	"OBJECT|FREQ":     {Categories: []string{"keyspace", "read", "slow"}, FirstKey: 2, LastKey: 2, Step: 1},
+	"OBJECT|REFCOUNT": {Categories: []string{"keyspace", "read", "slow"}, FirstKey: 2, LastKey: 2, Step: 1},
+	"OBJECT|HELP":     {Categories: []string{"keyspace", "slow"}},
+	"MEMORY|USAGE":    {Categories: []string{"read", "slow"}, FirstKey: 2, LastKey: 2, Step: 1},
+	"MEMORY|STATS":    {Categories: []string{"slow"}},
+	"MEMORY|DOCTOR":   {Categories: []string{"slow"}},
+	"MEMORY|HELP":     {Categories: []string{"slow"}},
+
+	"SELECT":   {Categories: []string{"fast", "connection"}},
+	"MOVE":     {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"SWAPDB":   {Categories: []string{"keyspace", "write", "fast", "dangerous"}},
+	"DBSIZE":   {Categories: []string{"keyspace", "read", "fast"}},
+	"FLUSHDB":  {Categories: []string{"keyspace", "write", "slow", "dangerous"}},
+	"FLUSHALL": {Categories: []string{"keyspace", "write", "slow", "dangerous"}},
+
+	"EXPIRE":      {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"PEXPIRE":     {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"EXPIREAT":    {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"PEXPIREAT":   {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"TTL":         {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"PTTL":        {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"EXPIRETIME":  {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"PEXPIRETIME": {Categories: []string{"keyspace", "read", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"PERSIST":     {Categories: []string{"keyspace", "write", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+
+	"GET":  {Categories: []string{"read", "string", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"SET":  {Categories: []string{"write", "string", "slow"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+	"INCR": {Categories: []string{"write", "string", "fast"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+
+	"RPUSH":  {Categories: []string{"write", "list", "fast"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+	"LPUSH":  {Categories: []string{"write", "list", "fast"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+	"LRANGE": {Categories: []string{"read", "list", "slow"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"LLEN":   {Categories: []string{"read", "list", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"LPOP":   {Categories: []string{"write", "list", "fast"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"BLPOP":  {Categories: []string{"write", "list", "slow", "blocking"}, FirstKey: 1, LastKey: -2, Step: 1},
+
+	"XADD":   {Categories: []string{"write", "stream", "fast"}, FirstKey: 1, LastKey: 1, Step: 1, DenyOOM: true},
+	"XRANGE": {Categories: []string{"read", "stream", "slow"}, FirstKey: 1, LastKey: 1, Step: 1},
+	"XREAD":  {Categories: []string{"read", "stream", "slow", "blocking"}, KeysFunc: xreadKeys},
+
+	"MULTI":   {Categories: []string{"fast", "transaction"}},
+	"EXEC":    {Categories: []string{"slow", "transaction"}},
+	"DISCARD": {Categories: []string{"fast", "transaction"}},
+
+	"ACL|SETUSER": {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|GETUSER": {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|DELUSER": {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|LIST":    {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|USERS":   {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|WHOAMI":  {Categories: []string{"slow"}},
+	"ACL|CAT":     {Categories: []string{"slow"}},
+	"ACL|LOG":     {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|LOAD":    {Categories: []string{"admin", "slow", "dangerous"}},
+	"ACL|SAVE":    {Categories: []string{"admin", "slow", "dangerous"}},
+
+	"CONFIG|GET":       {Categories: []string{"admin", "slow", "dangerous"}},
+	"CONFIG|SET":       {Categories: []string{"admin", "slow", "dangerous"}},
+	"CONFIG|REWRITE":   {Categories: []string{"admin", "slow", "dangerous"}},
+	"CONFIG|RESETSTAT": {Categories: []string{"admin", "slow", "dangerous"}},
+
+	"CLIENT|ID":      {Categories: []string{"slow", "connection"}},
+	"CLIENT|SETNAME": {Categories: []string{"slow", "connection"}},
+	"CLIENT|GETNAME": {Categories: []string{"slow", "connection"}},
+	"CLIENT|LIST":    {Categories: []string{"admin", "slow", "dangerous", "connection"}},
+	"CLIENT|INFO":    {Categories: []string{"slow", "connection"}},
+	"CLIENT|KILL":    {Categories: []string{"admin", "slow", "dangerous", "connection"}},
+	"CLIENT|REPLY":   {Categories: []string{"slow", "connection"}},
+	"CLIENT|PAUSE":   {Categories: []string{"admin", "slow", "dangerous", "connection"}},
+	"CLIENT|UNPAUSE": {Categories: []string{"admin", "slow", "dangerous", "connection"}},
+
+	"SUBSCRIBE":       {Categories: []string{"pubsub", "slow"}},
+	"UNSUBSCRIBE":     {Categories: []string{"pubsub", "slow"}},
+	"PSUBSCRIBE":      {Categories: []string{"pubsub", "slow"}},
+	"PUNSUBSCRIBE":    {Categories: []string{"pubsub", "slow"}},
+	"PUBLISH":         {Categories: []string{"pubsub", "fast"}},
+	"PUBSUB|CHANNELS": {Categories: []string{"pubsub", "slow"}},
+	"PUBSUB|NUMSUB":   {Categories: []string{"pubsub", "slow"}},
+	"PUBSUB|NUMPAT":   {Categories: []string{"pubsub", "slow"}},
+	"PUBSUB|HELP":     {Categories: []string{"slow"}},
+}
+
+// GetSpec은 명령어(서브커맨드가 있으면 서브커맨드)의 정보를 반환합니다
+// 표에 없는 명령어는 카테고리와 키가 없는 것으로 봅니다
+func (cm *CommandManger) GetSpec(command string, args [][]byte) CommandSpec {
+	if len(args) > 0 {
+		name := command + "|" + strings.ToUpper(string(args[0]))
+		if spec, ok := commandSpecs[name]; ok {
+			spec.Name = strings.ToLower(name)
+			return spec
+		}
+	}
+	spec := commandSpecs[command]
+	spec.Name = strings.ToLower(command)
+	return spec
+}
+
+// Keys는 인수 중 키에 해당하는 값들을 반환합니다 (args에는 명령어 이름이 빠져 있습니다)
+func (spec CommandSpec) Keys(args [][]byte) [][]byte {
+	if spec.KeysFunc != nil {
+		return spec.KeysFunc(args)
+	}
+	if spec.FirstKey == 0 {
+		return nil
+	}
+
+	last := spec.LastKey
+	if last < 0 {
+		last = len(args) + 1 + last
+	}
+	step := spec.Step
+	if step <= 0 {
+		step = 1
+	}
+
+	var keys [][]byte
+	for i := spec.FirstKey; i <= last && i-1 < len(args); i += step {
+		keys = append(keys, args[i-1])
+	}
+	return keys
+}
+
+// xreadKeys는 XREAD [COUNT n] [BLOCK ms] STREAMS key... id... 에서 키를 찾습니다
+func xreadKeys(args [][]byte) [][]byte {
+	for i, arg := range args {
+		if strings.EqualFold(string(arg), "STREAMS") {
+			streams := args[i+1:]
+			return streams[:len(streams)/2]
+		}
+	}
+	return nil
+}
+
+// HasCommand는 ACL 규칙에 쓸 수 있는 명령어인지 확인합니다 (소문자, "acl|setuser" 형식 가능)
+func (cm *CommandManger) HasCommand(name string) bool {
+	parent, _, _ := strings.Cut(strings.ToUpper(name), "|")
+	_, ok := cm.handlers[parent]
+	return ok
+}
+
+// CommandsInCategory는 카테고리에 속한 명령어 이름을 정렬해서 반환합니다
+func (cm *CommandManger) CommandsInCategory(category string) []string {
+	var names []string
+	for name, spec := range commandSpecs {
+		for _, c := range spec.Categories {
+			if c == category {
+				names = append(names, strings.ToLower(name))
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Categories는 ACL 카테고리 목록을 반환합니다
+func (cm *CommandManger) Categories() []string {
+	return aclCategories
+}
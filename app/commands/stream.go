@@ -23,7 +23,7 @@ func (cm *CommandManger) handleXAdd(e types.CommandEvent) {
 		for key, value := range args.Fields {
 			fields = append(fields, entity.FieldValue{Key: key, Value: value})
 		}
-		generatedId, err := cm.store.XAdd(args.Key, args.ID, fields)
+		generatedId, err := cm.db(e).XAdd(args.Key, args.ID, fields)
 		if err != nil {
 			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
 			return
@@ -36,7 +36,7 @@ func (cm *CommandManger) handleXAdd(e types.CommandEvent) {
 // handleXRange는 XRANGE 명령어를 처리합니다
 func (cm *CommandManger) handleXRange(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *XRangeArgs) {
-		entries, err := cm.store.XRange(args.Key, args.Start, args.End)
+		entries, err := cm.db(e).XRange(args.Key, args.Start, args.End)
 		if err != nil {
 			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
 			return
@@ -122,8 +122,16 @@ func (cm *CommandManger) handleXRead(e types.CommandEvent) {
 		//}
 
 		// 블로킹 연산이므로 고루틴에서 실행
+		db := cm.db(e)
 		go func() {
-			entries, err := cm.store.XRead(time.Duration(args.Timeout)*time.Millisecond, args.Keys, args.IDs)
+			// 이벤트 루프 밖에서 응답하므로 직접 전송합니다
+			defer e.Ctx.Flush()
+			if args.Block {
+				e.Ctx.SetBlocked(true)
+				defer e.Ctx.SetBlocked(false)
+			}
+
+			entries, err := db.XRead(args.Block, time.Duration(args.Timeout)*time.Millisecond, args.Keys, args.IDs)
 			if err != nil {
 				e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
 				return
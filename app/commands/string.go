@@ -15,7 +15,7 @@ func (cm *CommandManger) registerStringCommands() {
 
 func (cm *CommandManger) handleGet(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *GetArgs) {
-		value, exists := cm.store.Get(args.Key)
+		value, exists := cm.db(e).Get(args.Key)
 
 		if exists {
 			e.Ctx.Write(protocol.AppendBulkString([]byte{}, []byte(value)))
@@ -32,7 +32,7 @@ func (cm *CommandManger) handleSet(e types.CommandEvent) {
 			expire = *exp
 		}
 
-		cm.store.Set(args.Key, args.Value, expire)
+		cm.db(e).Set(args.Key, args.Value, expire)
 		e.Ctx.Write(protocol.AppendString([]byte{}, "OK"))
 	})
 
@@ -41,7 +41,7 @@ func (cm *CommandManger) handleSet(e types.CommandEvent) {
 
 func (cm *CommandManger) handleIncr(e types.CommandEvent) {
 	ParseAndExecute(e, func(args *IncrArgs) {
-		result, err := cm.store.Incr(args.Key)
+		result, err := cm.db(e).Incr(args.Key)
 
 		if err != nil {
 			e.Ctx.Write(protocol.AppendError([]byte{}, err.Error()))
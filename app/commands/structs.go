@@ -2,8 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/store"
 )
 
 // 기본 명령어 구조체들
@@ -209,7 +213,7 @@ func (args *XReadArgs) Validate() error {
 }
 
 type InfoArgs struct {
-	Type string `redis:"type"`
+	Section string `redis:"section,optional"`
 }
 
 func (args *InfoArgs) Validate() error {
@@ -224,3 +228,294 @@ type ReplConfArgs struct {
 func (args *ReplConfArgs) Validate() error {
 	return nil
 }
+
+// 인증 명령어 구조체들
+
+type AuthArgs struct {
+	First    string `redis:"username_or_password"`
+	Password string `redis:"password,optional"`
+}
+
+func (args *AuthArgs) Validate() error {
+	return nil
+}
+
+// 키 공간 명령어 구조체들
+
+// KeysArgs는 DEL, EXISTS, UNLINK, TOUCH처럼 키만 여러 개 받는 명령어의 인수입니다
+type KeysArgs struct {
+	Keys []string `redis:"keys,variadic"`
+}
+
+func (args *KeysArgs) Validate() error {
+	if len(args.Keys) == 0 {
+		return errWrongArity
+	}
+	return nil
+}
+
+// KeyArgs는 TTL, PERSIST처럼 키 하나만 받는 명령어의 인수입니다
+type KeyArgs struct {
+	Key string `redis:"key"`
+}
+
+func (args *KeyArgs) Validate() error {
+	return nil
+}
+
+// ExpireArgs는 EXPIRE, PEXPIRE, EXPIREAT, PEXPIREAT key value [NX|XX|GT|LT]의 인수입니다
+type ExpireArgs struct {
+	Key     string   `redis:"key"`
+	Value   string   `redis:"value"`
+	Options []string `redis:"options,variadic"`
+
+	Amount int64                 `redis:"-"`
+	Cond   store.ExpireCondition `redis:"-"`
+}
+
+func (args *ExpireArgs) Validate() error {
+	amount, err := strconv.ParseInt(args.Value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("value is not an integer or out of range")
+	}
+	args.Amount = amount
+
+	for _, opt := range args.Options {
+		switch strings.ToUpper(opt) {
+		case "NX":
+			args.Cond |= store.ExpireNX
+		case "XX":
+			args.Cond |= store.ExpireXX
+		case "GT":
+			args.Cond |= store.ExpireGT
+		case "LT":
+			args.Cond |= store.ExpireLT
+		default:
+			return fmt.Errorf("Unsupported option %s", opt)
+		}
+	}
+	if args.Cond&store.ExpireNX != 0 && args.Cond != store.ExpireNX {
+		return fmt.Errorf("NX and XX, GT or LT options at the same time are not compatible")
+	}
+	if args.Cond&store.ExpireGT != 0 && args.Cond&store.ExpireLT != 0 {
+		return fmt.Errorf("GT and LT options at the same time are not compatible")
+	}
+	return nil
+}
+
+// RenameArgs는 RENAME, RENAMENX 명령어의 인수입니다
+type RenameArgs struct {
+	Key    string `redis:"key"`
+	NewKey string `redis:"newkey"`
+}
+
+func (args *RenameArgs) Validate() error {
+	return nil
+}
+
+// CopyArgs는 COPY source destination [DB destination-db] [REPLACE]의 인수입니다
+type CopyArgs struct {
+	Source      string   `redis:"source"`
+	Destination string   `redis:"destination"`
+	Options     []string `redis:"options,variadic"`
+
+	DB      string `redis:"-"` // 비어 있으면 지금 DB입니다
+	Replace bool   `redis:"-"`
+}
+
+func (args *CopyArgs) Validate() error {
+	for i := 0; i < len(args.Options); i++ {
+		switch strings.ToUpper(args.Options[i]) {
+		case "REPLACE":
+			args.Replace = true
+		case "DB":
+			if i+1 >= len(args.Options) {
+				return fmt.Errorf("syntax error")
+			}
+			args.DB = args.Options[i+1]
+			i++
+		default:
+			return fmt.Errorf("syntax error")
+		}
+	}
+	return nil
+}
+
+// RestoreArgs는 RESTORE key ttl serialized-value [REPLACE] [ABSTTL] [IDLETIME seconds] [FREQ frequency]의 인수입니다
+type RestoreArgs struct {
+	Key     string   `redis:"key"`
+	TTL     string   `redis:"ttl"`
+	Payload string   `redis:"serialized-value"`
+	Options []string `redis:"options,variadic"`
+
+	TTLValue int64 `redis:"-"` // 밀리초, ABSTTL이면 unix 밀리초
+	Replace  bool  `redis:"-"`
+	AbsTTL   bool  `redis:"-"`
+	Idle     int64 `redis:"-"` // 초
+	Freq     int   `redis:"-"` // 음수면 주지 않은 것입니다
+}
+
+func (args *RestoreArgs) Validate() error {
+	ttl, err := strconv.ParseInt(args.TTL, 10, 64)
+	if err != nil {
+		return fmt.Errorf("value is not an integer or out of range")
+	}
+	if ttl < 0 {
+		return fmt.Errorf("Invalid TTL value, must be >= 0")
+	}
+	args.TTLValue = ttl
+	args.Freq = -1
+
+	hasIdle := false
+	for i := 0; i < len(args.Options); i++ {
+		switch strings.ToUpper(args.Options[i]) {
+		case "REPLACE":
+			args.Replace = true
+		case "ABSTTL":
+			args.AbsTTL = true
+		case "IDLETIME":
+			if i+1 >= len(args.Options) || args.Freq >= 0 {
+				return fmt.Errorf("syntax error")
+			}
+			idle, err := strconv.ParseInt(args.Options[i+1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("value is not an integer or out of range")
+			}
+			if idle < 0 {
+				return fmt.Errorf("Invalid IDLETIME value, must be >= 0")
+			}
+			args.Idle, hasIdle = idle, true
+			i++
+		case "FREQ":
+			if i+1 >= len(args.Options) || hasIdle {
+				return fmt.Errorf("syntax error")
+			}
+			freq, err := strconv.Atoi(args.Options[i+1])
+			if err != nil {
+				return fmt.Errorf("value is not an integer or out of range")
+			}
+			if freq < 0 || freq > 255 {
+				return fmt.Errorf("Invalid FREQ value, must be >= 0 and <= 255")
+			}
+			args.Freq = freq
+			i++
+		default:
+			return fmt.Errorf("syntax error")
+		}
+	}
+	return nil
+}
+
+// PatternArgs는 KEYS pattern의 인수입니다
+type PatternArgs struct {
+	Pattern string `redis:"pattern"`
+}
+
+func (args *PatternArgs) Validate() error {
+	return nil
+}
+
+// scanTypes는 SCAN TYPE에 줄 수 있는 타입 이름입니다
+var scanTypes = []string{"string", "list", "set", "zset", "hash", "stream"}
+
+// ScanArgs는 SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]의 인수입니다
+type ScanArgs struct {
+	Cursor  string   `redis:"cursor"`
+	Options []string `redis:"options,variadic"`
+
+	CursorValue uint64 `redis:"-"`
+	Match       string `redis:"-"`
+	Count       int    `redis:"-"`
+	Type        string `redis:"-"`
+}
+
+func (args *ScanArgs) Validate() error {
+	cursor, err := strconv.ParseUint(args.Cursor, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+	args.CursorValue = cursor
+	args.Match = "*"
+	args.Count = 10
+
+	for i := 0; i < len(args.Options); i++ {
+		if i+1 >= len(args.Options) {
+			return fmt.Errorf("syntax error")
+		}
+		value := args.Options[i+1]
+		switch strings.ToUpper(args.Options[i]) {
+		case "MATCH":
+			args.Match = value
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("value is not an integer or out of range")
+			}
+			if count < 1 {
+				return fmt.Errorf("syntax error")
+			}
+			args.Count = count
+		case "TYPE":
+			args.Type = strings.ToLower(value)
+			if !slices.Contains(scanTypes, args.Type) {
+				return fmt.Errorf("unknown type name '%s'", value)
+			}
+		default:
+			return fmt.Errorf("syntax error")
+		}
+		i++
+	}
+	return nil
+}
+
+// 데이터베이스 명령어 구조체들
+
+type SelectArgs struct {
+	Index string `redis:"index"`
+}
+
+func (args *SelectArgs) Validate() error {
+	return nil
+}
+
+type MoveArgs struct {
+	Key string `redis:"key"`
+	DB  string `redis:"db"`
+}
+
+func (args *MoveArgs) Validate() error {
+	return nil
+}
+
+type SwapDBArgs struct {
+	First  string `redis:"index1"`
+	Second string `redis:"index2"`
+}
+
+func (args *SwapDBArgs) Validate() error {
+	return nil
+}
+
+type DBSizeArgs struct{}
+
+func (args *DBSizeArgs) Validate() error {
+	return nil
+}
+
+// FlushArgs는 FLUSHDB, FLUSHALL [ASYNC|SYNC]의 인수입니다
+type FlushArgs struct {
+	Mode string `redis:"mode,optional"`
+
+	Async bool `redis:"-"`
+}
+
+func (args *FlushArgs) Validate() error {
+	switch strings.ToUpper(args.Mode) {
+	case "", "SYNC":
+	case "ASYNC":
+		args.Async = true
+	default:
+		return fmt.Errorf("syntax error")
+	}
+	return nil
+}
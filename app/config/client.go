@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ClientClass는 출력 버퍼 제한을 구분하는 클라이언트 종류입니다
+type ClientClass int
+
+const (
+	ClientNormal ClientClass = iota
+	ClientReplica
+	ClientPubSub
+	clientClassCount
+)
+
+func (c ClientClass) String() string {
+	switch c {
+	case ClientReplica:
+		return "replica"
+	case ClientPubSub:
+		return "pubsub"
+	default:
+		return "normal"
+	}
+}
+
+// ParseClientClass는 client-output-buffer-limit의 클래스 이름을 해석합니다 (slave는 replica의 별칭)
+func ParseClientClass(name string) (ClientClass, bool) {
+	switch strings.ToLower(name) {
+	case "normal":
+		return ClientNormal, true
+	case "replica", "slave":
+		return ClientReplica, true
+	case "pubsub":
+		return ClientPubSub, true
+	default:
+		return 0, false
+	}
+}
+
+// OutputBufferLimit는 클라이언트 출력 버퍼 제한입니다
+// Hard를 넘거나 Soft를 SoftSeconds초보다 오래 넘고 있으면 연결을 끊습니다 (0이면 제한 없음)
+type OutputBufferLimit struct {
+	Hard        int64
+	Soft        int64
+	SoftSeconds int
+}
+
+// OutputBufferLimits는 클래스별 출력 버퍼 제한 표입니다
+type OutputBufferLimits [clientClassCount]OutputBufferLimit
+
+// OutputLimiter는 클라이언트 클래스별 출력 버퍼 제한을 알려 줍니다
+type OutputLimiter interface {
+	OutputBufferLimit(class ClientClass) OutputBufferLimit
+}
+
+func (limits *OutputBufferLimits) OutputBufferLimit(class ClientClass) OutputBufferLimit {
+	return limits[class]
+}
+
+// OutputBufferLimit는 CONFIG SET과 겹치지 않도록 잠금을 잡고 현재 제한을 읽습니다
+func (c *Config) OutputBufferLimit(class ClientClass) OutputBufferLimit {
+	c.RLock()
+	defer c.RUnlock()
+	return c.ClientOutputBufferLimit[class]
+}
+
+func DefaultOutputBufferLimits() OutputBufferLimits {
+	return OutputBufferLimits{
+		ClientNormal:  {},
+		ClientReplica: {Hard: 256 * 1024 * 1024, Soft: 64 * 1024 * 1024, SoftSeconds: 60},
+		ClientPubSub:  {Hard: 32 * 1024 * 1024, Soft: 8 * 1024 * 1024, SoftSeconds: 60},
+	}
+}
+
+// ParseOutputBufferLimits는 "<class> <hard> <soft> <soft-seconds>" 묶음을 하나 이상 읽어 limits에 반영합니다
+// 하나라도 잘못되면 limits는 바뀌지 않습니다
+func ParseOutputBufferLimits(value string, limits *OutputBufferLimits) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields)%4 != 0 {
+		return fmt.Errorf("wrong number of arguments in buffer limit configuration")
+	}
+
+	parsed := *limits
+	for i := 0; i < len(fields); i += 4 {
+		class, ok := ParseClientClass(fields[i])
+		if !ok {
+			return fmt.Errorf("invalid client class specified in buffer limit configuration")
+		}
+		hard, err := ParseMemory(fields[i+1])
+		if err != nil {
+			return fmt.Errorf("error in hard, soft or soft_seconds setting in buffer limit configuration")
+		}
+		soft, err := ParseMemory(fields[i+2])
+		if err != nil {
+			return fmt.Errorf("error in hard, soft or soft_seconds setting in buffer limit configuration")
+		}
+		seconds, err := strconv.Atoi(fields[i+3])
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("error in hard, soft or soft_seconds setting in buffer limit configuration")
+		}
+		parsed[class] = OutputBufferLimit{Hard: hard, Soft: soft, SoftSeconds: seconds}
+	}
+
+	*limits = parsed
+	return nil
+}
+
+// String은 limits를 client-output-buffer-limit 설정 형식으로 돌려줍니다
+func (limits OutputBufferLimits) String() string {
+	parts := make([]string, 0, len(limits))
+	for class, limit := range limits {
+		parts = append(parts, fmt.Sprintf("%s %d %d %d", ClientClass(class), limit.Hard, limit.Soft, limit.SoftSeconds))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Config는 서버 설정 값을 모아 둔 구조체입니다
+// CONFIG SET이 이벤트 루프에서 값을 바꾸므로, 연결 고루틴에서 읽을 때는 RLock을 잡아야 합니다
+type Config struct {
+	sync.RWMutex
+
+	Port      int
+	ReplicaOf string
+
+	// 네트워크
+	Bind          []string // 앞에 '-'가 붙은 주소는 열지 못해도 넘어갑니다
+	ProtectedMode bool
+
+	// 클라이언트 연결 (초 단위, 0이면 끕니다)
+	Timeout      int
+	TCPKeepAlive int
+	MaxClients   int
+
+	// 인증
+	RequirePass  string
+	MasterAuth   string
+	ACLFile      string
+	ACLLogMaxLen int
+
+	// 프로토콜 제한
+	ProtoMaxBulkLen        int64
+	ClientQueryBufferLimit int64
+
+	// 클라이언트 출력 버퍼 제한 (client-output-buffer-limit)
+	ClientOutputBufferLimit OutputBufferLimits
+
+	// TLS
+	TLSPort        int
+	TLSCertFile    string
+	TLSKeyFile     string
+	TLSCACertFile  string
+	TLSAuthClients string // yes, no, optional
+	TLSReplication bool
+
+	// 유닉스 소켓
+	UnixSocket     string
+	UnixSocketPerm os.FileMode // 0이면 umask에 따른 기본 권한을 그대로 둡니다
+
+	// 논리 데이터베이스 수 (SELECT 0 ~ databases-1)
+	Databases int
+
+	// lazyfree (큰 값을 백그라운드에서 해제할 삭제 종류)
+	LazyfreeLazyEviction  bool
+	LazyfreeLazyExpire    bool
+	LazyfreeLazyServerDel bool
+	LazyfreeLazyUserDel   bool
+
+	// 메모리 제한 (maxmemory가 0이면 제한하지 않습니다)
+	MaxMemory        int64
+	MaxMemoryPolicy  string
+	MaxMemorySamples int
+	LFULogFactor     int
+	LFUDecayTime     int
+
+	// 키 공간 이벤트를 pub/sub으로 알릴 종류 (비어 있으면 알리지 않습니다)
+	NotifyKeyspaceEvents KeyspaceEvents
+
+	// 주기 작업 (hz는 초당 실행 횟수, active-expire-effort는 1~10)
+	Hz                 int
+	ActiveExpireEffort int
+
+	// 파일
+	Dir        string
+	DBFilename string
+
+	// 자료구조 인코딩 기준
+	ListMaxListpackSize  int
+	ListCompressDepth    int
+	StreamNodeMaxBytes   int64
+	StreamNodeMaxEntries int
+
+	file  string // CONFIG REWRITE가 다시 쓸 설정 파일 (절대 경로)
+	hooks map[string][]func(c *Config)
+}
+
+// MaxMemoryPolicies는 maxmemory-policy에 줄 수 있는 값입니다
+var MaxMemoryPolicies = []string{
+	"volatile-lru", "volatile-lfu", "volatile-random", "volatile-ttl",
+	"allkeys-lru", "allkeys-lfu", "allkeys-random", "noeviction",
+}
+
+// Default는 Redis 기본값으로 채운 설정을 반환합니다
+func Default() *Config {
+	return &Config{
+		Port: 6379,
+
+		Bind:          []string{"*", "-::*"},
+		ProtectedMode: true,
+
+		TCPKeepAlive: 300,
+		MaxClients:   10000,
+
+		Databases: 16,
+
+		MaxMemoryPolicy:  "noeviction",
+		MaxMemorySamples: 5,
+		LFULogFactor:     10,
+		LFUDecayTime:     1,
+
+		Hz:                 10,
+		ActiveExpireEffort: 1,
+
+		ProtoMaxBulkLen:        512 * 1024 * 1024,
+		ClientQueryBufferLimit: 1024 * 1024 * 1024,
+
+		ClientOutputBufferLimit: DefaultOutputBufferLimits(),
+
+		TLSAuthClients: "yes",
+
+		ACLLogMaxLen: 128,
+
+		DBFilename: "dump.rdb",
+
+		ListMaxListpackSize:  -2,
+		StreamNodeMaxBytes:   4096,
+		StreamNodeMaxEntries: 100,
+	}
+}
+
+// ParseBool은 redis.conf 형식의 yes/no 값을 해석합니다
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("argument must be 'yes' or 'no'")
+	}
+}
+
+// ParseFileMode는 unixsocketperm처럼 8진수로 적은 권한 값을 해석합니다
+func ParseFileMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket file permissions")
+	}
+	return os.FileMode(mode), nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "redis.conf")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadArgs(t *testing.T) {
+	path := writeConfigFile(t, `# 주석
+port 7000
+requirepass "with space"
+client-output-buffer-limit replica 1mb 512kb 10
+slaveof 10.0.0.1 6379
+`)
+
+	c := Default()
+	if err := c.LoadArgs([]string{path, "--port", "7001", "--proto-max-bulk-len", "1mb"}); err != nil {
+		t.Fatalf("LoadArgs 실패: %v", err)
+	}
+
+	if c.Port != 7001 {
+		t.Errorf("명령줄 옵션이 파일 값을 덮어써야 함. got=%d", c.Port)
+	}
+	if c.RequirePass != "with space" {
+		t.Errorf("따옴표로 감싼 값이 다름. got=%q", c.RequirePass)
+	}
+	if c.ReplicaOf != "10.0.0.1 6379" {
+		t.Errorf("slaveof 별칭이 반영되어야 함. got=%q", c.ReplicaOf)
+	}
+	if c.ProtoMaxBulkLen != 1024*1024 {
+		t.Errorf("proto-max-bulk-len이 다름. got=%d", c.ProtoMaxBulkLen)
+	}
+	if limit := c.ClientOutputBufferLimit[ClientReplica]; limit.Hard != 1024*1024 || limit.SoftSeconds != 10 {
+		t.Errorf("client-output-buffer-limit이 다름. got=%+v", limit)
+	}
+
+	if err := c.LoadArgs([]string{"--replicaof", "localhost 6380"}); err != nil || c.ReplicaOf != "localhost 6380" {
+		t.Errorf("한 인수로 묶인 replicaof도 받아야 함. got=%q, err=%v", c.ReplicaOf, err)
+	}
+	if err := c.LoadArgs([]string{"--nosuch", "1"}); err == nil {
+		t.Errorf("모르는 옵션은 에러가 나야 함")
+	}
+	if err := Default().LoadFile(writeConfigFile(t, "port 1\nport abc\n")); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("잘못된 줄 번호가 에러에 있어야 함. got=%v", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	c := Default()
+	got := c.Get("tls-*-file", "PORT")
+	want := [][2]string{{"port", "6379"}, {"tls-ca-cert-file", ""}, {"tls-cert-file", ""}, {"tls-key-file", ""}}
+	if len(got) != len(want) {
+		t.Fatalf("CONFIG GET 결과가 다름. got=%q, want=%q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CONFIG GET 결과가 다름. got=%q, want=%q", got[i], want[i])
+		}
+	}
+
+	if got := c.Get("slaveof"); len(got) != 1 || got[0][0] != "slaveof" {
+		t.Errorf("별칭을 정확히 물으면 보여 줘야 함. got=%q", got)
+	}
+	for _, pair := range c.Get("*") {
+		if pair[0] == "slaveof" {
+			t.Errorf("패턴 검색에는 별칭이 나오면 안 됨")
+		}
+	}
+}
+
+func TestSetRuntime(t *testing.T) {
+	c := Default()
+	changed := ""
+	c.OnChange("requirepass", func(c *Config) { changed = c.RequirePass })
+
+	if err := c.SetRuntime([][2]string{{"requirepass", "secret"}, {"proto-max-bulk-len", "1kb"}}); err != nil {
+		t.Fatalf("SetRuntime 실패: %v", err)
+	}
+	if changed != "secret" || c.ProtoMaxBulkLen != 1024 {
+		t.Errorf("값과 훅이 반영되어야 함. hook=%q, bulk=%d", changed, c.ProtoMaxBulkLen)
+	}
+
+	err := c.SetRuntime([][2]string{{"requirepass", "other"}, {"proto-max-bulk-len", "abc"}})
+	if err == nil || !strings.Contains(err.Error(), "'proto-max-bulk-len'") {
+		t.Errorf("잘못된 값은 에러가 나야 함. got=%v", err)
+	}
+	if c.RequirePass != "secret" {
+		t.Errorf("실패한 CONFIG SET은 앞의 값도 되돌려야 함. got=%q", c.RequirePass)
+	}
+
+	if err := c.SetRuntime([][2]string{{"port", "1234"}}); err == nil || !strings.Contains(err.Error(), "immutable") {
+		t.Errorf("바꿀 수 없는 항목은 거부되어야 함. got=%v", err)
+	}
+	if err := c.SetRuntime([][2]string{{"nosuch", "1"}}); err == nil || !strings.Contains(err.Error(), "Unknown option") {
+		t.Errorf("모르는 항목은 거부되어야 함. got=%v", err)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	path := writeConfigFile(t, `# 맨 위 주석
+port 7000
+
+# 버퍼 제한
+client-output-buffer-limit normal 0 0 0
+client-output-buffer-limit replica 1mb 512kb 10
+requirepass old
+requirepass duplicated
+`)
+
+	c := Default()
+	if err := c.LoadArgs([]string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Rewrite(); err != nil {
+		t.Fatalf("Rewrite 실패: %v", err)
+	}
+
+	if err := c.SetRuntime([][2]string{{"requirepass", "new pass"}, {"list-max-listpack-size", "128"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Rewrite(); err != nil {
+		t.Fatalf("Rewrite 실패: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# 맨 위 주석
+port 7000
+
+# 버퍼 제한
+client-output-buffer-limit normal 0 0 0
+client-output-buffer-limit replica 1048576 524288 10
+requirepass "new pass"
+client-output-buffer-limit pubsub 33554432 8388608 60
+# Generated by CONFIG REWRITE
+list-max-listpack-size 128
+`
+	if string(data) != want {
+		t.Errorf("다시 쓴 설정 파일이 다름.\ngot:\n%s\nwant:\n%s", data, want)
+	}
+
+	reloaded := Default()
+	if err := reloaded.LoadFile(path); err != nil || reloaded.RequirePass != "new pass" {
+		t.Errorf("다시 쓴 파일을 읽을 수 있어야 함. got=%q, err=%v", reloaded.RequirePass, err)
+	}
+
+	if err := Default().Rewrite(); err == nil {
+		t.Errorf("설정 파일 없이 시작하면 REWRITE는 실패해야 함")
+	}
+}
+
+func TestKeyspaceEvents(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"", ""},
+		{"KEA", "AKE"},
+		{"Ex", "xE"},
+		{"Kg$lshzxetd", "AK"},
+		{"El$n", "$lEn"},
+		{"Km", "Km"},
+	} {
+		flags, err := ParseKeyspaceEvents(tc.in)
+		if err != nil {
+			t.Fatalf("ParseKeyspaceEvents(%q) 실패: %v", tc.in, err)
+		}
+		if got := flags.String(); got != tc.want {
+			t.Errorf("ParseKeyspaceEvents(%q).String() = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+	if _, err := ParseKeyspaceEvents("KEQ"); err == nil {
+		t.Errorf("모르는 문자는 에러가 나야 함")
+	}
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+)
+
+// CONFIG REWRITE가 파일 끝에 새 항목을 덧붙일 때 앞에 적는 표시입니다
+const rewriteSignature = "# Generated by CONFIG REWRITE"
+
+// LoadArgs는 redis-server와 같은 명령줄 인수를 읽습니다
+// 첫 인수가 --로 시작하지 않으면 설정 파일 경로로 보고 먼저 읽은 뒤, --name value... 옵션을 그 위에 덮어씁니다
+func (c *Config) LoadArgs(args []string) error {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		if err := c.LoadFile(args[0]); err != nil {
+			return err
+		}
+		args = args[1:]
+	}
+
+	for i := 0; i < len(args); {
+		name, ok := strings.CutPrefix(args[i], "--")
+		if !ok {
+			return fmt.Errorf("invalid option '%s', options must start with --", args[i])
+		}
+		i++
+
+		var values []string
+		for i < len(args) && !strings.HasPrefix(args[i], "--") {
+			values = append(values, args[i])
+			i++
+		}
+		if err := c.Set(name, strings.Join(values, " ")); err != nil {
+			return fmt.Errorf("option '--%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+// LoadFile은 redis.conf 형식의 설정 파일을 읽습니다
+// 한 줄에 "이름 값..." 하나씩이며, #으로 시작하는 줄과 빈 줄은 건너뜁니다
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't open config file '%s': %v", path, err)
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		args, err := protocol.SplitArgs(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: Unbalanced quotes in configuration line", path, i+1)
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		values := make([]string, len(args)-1)
+		for j, arg := range args[1:] {
+			values[j] = string(arg)
+		}
+		if err := c.Set(string(args[0]), strings.Join(values, " ")); err != nil {
+			return fmt.Errorf("%s:%d: '%s': %v", path, i+1, line, err)
+		}
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	c.Lock()
+	c.file = abs
+	c.Unlock()
+	return nil
+}
+
+// Rewrite는 현재 설정을 설정 파일에 다시 씁니다 (CONFIG REWRITE)
+// 주석과 모르는 줄은 그대로 두고, 이미 있는 항목은 제자리에서 값만 바꾸며,
+// 파일에 없던 항목은 기본값과 다를 때만 파일 끝에 덧붙입니다
+func (c *Config) Rewrite() error {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.file == "" {
+		return fmt.Errorf("The server is running without a config file")
+	}
+
+	data, err := os.ReadFile(c.file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Rewriting config file: %v", err)
+	}
+
+	var lines []string
+	if text := strings.TrimRight(string(data), "\n"); text != "" {
+		lines = strings.Split(text, "\n")
+	}
+
+	written := make(map[*param]int)
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == rewriteSignature {
+			continue
+		}
+		if trimmed == "" || trimmed[0] == '#' {
+			out = append(out, line)
+			continue
+		}
+
+		args, err := protocol.SplitArgs(trimmed)
+		if err != nil || len(args) == 0 {
+			out = append(out, line)
+			continue
+		}
+		p, ok := lookupParam(string(args[0]))
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		// 같은 항목이 여러 줄이면 새 값을 차례로 채우고, 남는 줄은 지웁니다
+		values := p.rewriteValues(c)
+		if n := written[p]; n < len(values) {
+			out = append(out, p.name+" "+values[n])
+		}
+		written[p]++
+	}
+
+	defaults := Default()
+	var appended []string
+	for _, p := range uniqueParams() {
+		values := p.rewriteValues(c)
+		n, inFile := written[p]
+		if !inFile && p.get(c) == p.get(defaults) {
+			continue
+		}
+		for ; n < len(values); n++ {
+			appended = append(appended, p.name+" "+values[n])
+		}
+	}
+	if len(appended) > 0 {
+		out = append(out, rewriteSignature)
+		out = append(out, appended...)
+	}
+
+	return writeFileAtomic(c.file, []byte(strings.Join(out, "\n")+"\n"))
+}
+
+// rewriteValues는 CONFIG REWRITE에서 이 항목을 적을 줄마다의 값입니다
+func (p *param) rewriteValues(c *Config) []string {
+	if p.rewrite != nil {
+		return p.rewrite(c)
+	}
+	return []string{p.get(c)}
+}
+
+// uniqueParams는 별칭을 뺀 설정 항목을 이름 순서로 반환합니다
+func uniqueParams() []*param {
+	list := make([]*param, 0, len(params))
+	for name, p := range params {
+		if name == p.name {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+// quoteValue는 빈 문자열이나 공백, 따옴표가 든 값을 설정 파일에서 한 인수로 읽히도록 감쌉니다
+func quoteValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"'\\") {
+		return value
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString("\\n")
+		case '\r':
+			sb.WriteString("\\r")
+		case '\t':
+			sb.WriteString("\\t")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// writeFileAtomic은 임시 파일에 쓴 뒤 이름을 바꿔, 중간에 실패해도 기존 파일이 깨지지 않게 합니다
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return fmt.Errorf("Rewriting config file: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("Rewriting config file: %v", err)
+	}
+	return nil
+}
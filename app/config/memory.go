@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseMemory는 "512mb", "1gb", "64k" 같은 redis.conf 형식의 크기 값을 바이트 수로 변환합니다
+// k/m/g는 1000 단위, kb/mb/gb는 1024 단위입니다
+func ParseMemory(value string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+
+	units := []struct {
+		suffix string
+		mul    int64
+	}{
+		{"gb", 1024 * 1024 * 1024},
+		{"mb", 1024 * 1024},
+		{"kb", 1024},
+		{"g", 1000 * 1000 * 1000},
+		{"m", 1000 * 1000},
+		{"k", 1000},
+		{"b", 1},
+	}
+
+	mul := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSuffix(s, unit.suffix)
+			mul = unit.mul
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid memory value '%s'", value)
+	}
+	return n * mul, nil
+}
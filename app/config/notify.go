@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyspaceEvents는 notify-keyspace-events 플래그입니다
+// K, E는 보낼 채널 종류이고 나머지는 알릴 이벤트 종류입니다
+type KeyspaceEvents int
+
+const (
+	NotifyKeyspace KeyspaceEvents = 1 << iota // K: __keyspace@<db>__:<key> 채널
+	NotifyKeyevent                            // E: __keyevent@<db>__:<event> 채널
+	NotifyGeneric                             // g: DEL, EXPIRE, RENAME 같은 타입과 상관없는 명령어
+	NotifyString                              // $
+	NotifyList                                // l
+	NotifySet                                 // s
+	NotifyHash                                // h
+	NotifyZSet                                // z
+	NotifyExpired                             // x: 만료로 지운 키
+	NotifyEvicted                             // e: maxmemory로 쫓아낸 키
+	NotifyStream                              // t
+	NotifyKeyMiss                             // m: 없는 키를 읽음 (A에 들어가지 않습니다)
+	NotifyModule                              // d
+	NotifyNew                                 // n: 새로 만든 키 (A에 들어가지 않습니다)
+
+	// NotifyAll은 A 별칭입니다
+	NotifyAll = NotifyGeneric | NotifyString | NotifyList | NotifySet | NotifyHash | NotifyZSet |
+		NotifyExpired | NotifyEvicted | NotifyStream | NotifyModule
+)
+
+// keyspaceEventClasses는 A를 풀어 쓸 때의 순서입니다
+var keyspaceEventClasses = []struct {
+	flag KeyspaceEvents
+	char byte
+}{
+	{NotifyGeneric, 'g'}, {NotifyString, '$'}, {NotifyList, 'l'}, {NotifySet, 's'}, {NotifyHash, 'h'},
+	{NotifyZSet, 'z'}, {NotifyExpired, 'x'}, {NotifyEvicted, 'e'}, {NotifyStream, 't'}, {NotifyModule, 'd'},
+}
+
+// ParseKeyspaceEvents는 "KEA", "Ex$" 같은 notify-keyspace-events 값을 해석합니다
+func ParseKeyspaceEvents(value string) (KeyspaceEvents, error) {
+	var flags KeyspaceEvents
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case 'A':
+			flags |= NotifyAll
+		case 'K':
+			flags |= NotifyKeyspace
+		case 'E':
+			flags |= NotifyKeyevent
+		case 'm':
+			flags |= NotifyKeyMiss
+		case 'n':
+			flags |= NotifyNew
+		default:
+			found := false
+			for _, class := range keyspaceEventClasses {
+				if class.char == c {
+					flags |= class.flag
+					found = true
+				}
+			}
+			if !found {
+				return 0, fmt.Errorf("Invalid event class character. Use 'Ag$lshzxeKEtmdn'.")
+			}
+		}
+	}
+	return flags, nil
+}
+
+// String은 CONFIG GET에 보여 줄 정규화한 값입니다 (모든 이벤트 종류가 켜져 있으면 A로 줄입니다)
+func (flags KeyspaceEvents) String() string {
+	var sb strings.Builder
+	if flags&NotifyAll == NotifyAll {
+		sb.WriteByte('A')
+	} else {
+		for _, class := range keyspaceEventClasses {
+			if flags&class.flag != 0 {
+				sb.WriteByte(class.char)
+			}
+		}
+	}
+	if flags&NotifyKeyspace != 0 {
+		sb.WriteByte('K')
+	}
+	if flags&NotifyKeyevent != 0 {
+		sb.WriteByte('E')
+	}
+	if flags&NotifyKeyMiss != 0 {
+		sb.WriteByte('m')
+	}
+	if flags&NotifyNew != 0 {
+		sb.WriteByte('n')
+	}
+	return sb.String()
+}
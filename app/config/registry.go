@@ -0,0 +1,374 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/glob"
+)
+
+// param은 설정 레지스트리에 등록된 항목 하나입니다
+type param struct {
+	name    string
+	alias   string // 예전 이름 (slaveof 등)
+	mutable bool   // CONFIG SET으로 바꿀 수 있는지
+	get     func(c *Config) string
+	set     func(c *Config, value string) error
+
+	// rewrite는 CONFIG REWRITE 때 한 줄에 하나씩 쓸 값들입니다 (없으면 get 값 한 줄)
+	rewrite func(c *Config) []string
+}
+
+func stringParam(name string, mutable bool, field func(c *Config) *string) param {
+	return param{
+		name:    name,
+		mutable: mutable,
+		get:     func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+		rewrite: func(c *Config) []string { return []string{quoteValue(*field(c))} },
+	}
+}
+
+func intParam(name string, mutable bool, min, max int, field func(c *Config) *int) param {
+	return param{
+		name:    name,
+		mutable: mutable,
+		get:     func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("argument couldn't be parsed into an integer")
+			}
+			if n < min || n > max {
+				return fmt.Errorf("argument must be between %d and %d inclusive", min, max)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+func boolParam(name string, mutable bool, field func(c *Config) *bool) param {
+	return param{
+		name:    name,
+		mutable: mutable,
+		get: func(c *Config) string {
+			if *field(c) {
+				return "yes"
+			}
+			return "no"
+		},
+		set: func(c *Config, value string) error {
+			b, err := ParseBool(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = b
+			return nil
+		},
+	}
+}
+
+func memoryParam(name string, mutable bool, field func(c *Config) *int64) param {
+	return param{
+		name:    name,
+		mutable: mutable,
+		get:     func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+		set: func(c *Config, value string) error {
+			n, err := ParseMemory(value)
+			if err != nil {
+				return fmt.Errorf("argument must be a memory value")
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+func enumParam(name string, mutable bool, values []string, field func(c *Config) *string) param {
+	return param{
+		name:    name,
+		mutable: mutable,
+		get:     func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			for _, v := range values {
+				if strings.EqualFold(v, value) {
+					*field(c) = v
+					return nil
+				}
+			}
+			return fmt.Errorf("argument(s) must be one of the following: %s", strings.Join(values, ", "))
+		},
+	}
+}
+
+// params는 이름으로 찾을 수 있는 설정 항목 표입니다
+var params = buildParams()
+
+func buildParams() map[string]*param {
+	list := []param{
+		intParam("port", false, 0, 65535, func(c *Config) *int { return &c.Port }),
+		{
+			name:  "replicaof",
+			alias: "slaveof",
+			get:   func(c *Config) string { return c.ReplicaOf },
+			set: func(c *Config, value string) error {
+				fields := strings.Fields(value)
+				if len(fields) == 0 || (len(fields) == 2 && strings.EqualFold(fields[0], "no") && strings.EqualFold(fields[1], "one")) {
+					c.ReplicaOf = ""
+					return nil
+				}
+				if len(fields) != 2 {
+					return fmt.Errorf("wrong number of arguments")
+				}
+				if _, err := strconv.Atoi(fields[1]); err != nil {
+					return fmt.Errorf("Invalid master port")
+				}
+				c.ReplicaOf = fields[0] + " " + fields[1]
+				return nil
+			},
+		},
+		{
+			name: "bind",
+			get:  func(c *Config) string { return strings.Join(c.Bind, " ") },
+			set: func(c *Config, value string) error {
+				c.Bind = strings.Fields(value)
+				return nil
+			},
+			rewrite: func(c *Config) []string {
+				if len(c.Bind) == 0 {
+					return []string{quoteValue("")}
+				}
+				return []string{strings.Join(c.Bind, " ")}
+			},
+		},
+		boolParam("protected-mode", true, func(c *Config) *bool { return &c.ProtectedMode }),
+		intParam("timeout", true, 0, 1<<31-1, func(c *Config) *int { return &c.Timeout }),
+		intParam("tcp-keepalive", true, 0, 1<<31-1, func(c *Config) *int { return &c.TCPKeepAlive }),
+		intParam("maxclients", true, 1, 1<<31-1, func(c *Config) *int { return &c.MaxClients }),
+
+		boolParam("lazyfree-lazy-eviction", true, func(c *Config) *bool { return &c.LazyfreeLazyEviction }),
+		boolParam("lazyfree-lazy-expire", true, func(c *Config) *bool { return &c.LazyfreeLazyExpire }),
+		boolParam("lazyfree-lazy-server-del", true, func(c *Config) *bool { return &c.LazyfreeLazyServerDel }),
+		boolParam("lazyfree-lazy-user-del", true, func(c *Config) *bool { return &c.LazyfreeLazyUserDel }),
+
+		memoryParam("maxmemory", true, func(c *Config) *int64 { return &c.MaxMemory }),
+		enumParam("maxmemory-policy", true, MaxMemoryPolicies, func(c *Config) *string { return &c.MaxMemoryPolicy }),
+		intParam("maxmemory-samples", true, 1, 64, func(c *Config) *int { return &c.MaxMemorySamples }),
+		intParam("lfu-log-factor", true, 0, 1<<31-1, func(c *Config) *int { return &c.LFULogFactor }),
+		intParam("lfu-decay-time", true, 0, 1<<31-1, func(c *Config) *int { return &c.LFUDecayTime }),
+
+		{
+			name:    "notify-keyspace-events",
+			mutable: true,
+			get:     func(c *Config) string { return c.NotifyKeyspaceEvents.String() },
+			set: func(c *Config, value string) error {
+				flags, err := ParseKeyspaceEvents(value)
+				if err != nil {
+					return err
+				}
+				c.NotifyKeyspaceEvents = flags
+				return nil
+			},
+			rewrite: func(c *Config) []string { return []string{quoteValue(c.NotifyKeyspaceEvents.String())} },
+		},
+
+		intParam("databases", false, 1, 1<<31-1, func(c *Config) *int { return &c.Databases }),
+		intParam("hz", true, 1, 500, func(c *Config) *int { return &c.Hz }),
+		intParam("active-expire-effort", true, 1, 10, func(c *Config) *int { return &c.ActiveExpireEffort }),
+
+		stringParam("requirepass", true, func(c *Config) *string { return &c.RequirePass }),
+		stringParam("masterauth", true, func(c *Config) *string { return &c.MasterAuth }),
+		stringParam("aclfile", false, func(c *Config) *string { return &c.ACLFile }),
+		intParam("acllog-max-len", true, 0, 1<<31-1, func(c *Config) *int { return &c.ACLLogMaxLen }),
+
+		memoryParam("proto-max-bulk-len", true, func(c *Config) *int64 { return &c.ProtoMaxBulkLen }),
+		memoryParam("client-query-buffer-limit", true, func(c *Config) *int64 { return &c.ClientQueryBufferLimit }),
+		{
+			name:    "client-output-buffer-limit",
+			mutable: true,
+			get:     func(c *Config) string { return c.ClientOutputBufferLimit.String() },
+			set: func(c *Config, value string) error {
+				return ParseOutputBufferLimits(value, &c.ClientOutputBufferLimit)
+			},
+			rewrite: func(c *Config) []string {
+				lines := make([]string, 0, len(c.ClientOutputBufferLimit))
+				for class, limit := range c.ClientOutputBufferLimit {
+					lines = append(lines, fmt.Sprintf("%s %d %d %d", ClientClass(class), limit.Hard, limit.Soft, limit.SoftSeconds))
+				}
+				return lines
+			},
+		},
+
+		intParam("tls-port", false, 0, 65535, func(c *Config) *int { return &c.TLSPort }),
+		stringParam("tls-cert-file", false, func(c *Config) *string { return &c.TLSCertFile }),
+		stringParam("tls-key-file", false, func(c *Config) *string { return &c.TLSKeyFile }),
+		stringParam("tls-ca-cert-file", false, func(c *Config) *string { return &c.TLSCACertFile }),
+		enumParam("tls-auth-clients", false, []string{"yes", "no", "optional"}, func(c *Config) *string { return &c.TLSAuthClients }),
+		boolParam("tls-replication", true, func(c *Config) *bool { return &c.TLSReplication }),
+
+		stringParam("unixsocket", false, func(c *Config) *string { return &c.UnixSocket }),
+		{
+			name: "unixsocketperm",
+			get:  func(c *Config) string { return strconv.FormatUint(uint64(c.UnixSocketPerm), 8) },
+			set: func(c *Config, value string) error {
+				mode, err := ParseFileMode(value)
+				if err != nil {
+					return err
+				}
+				c.UnixSocketPerm = mode
+				return nil
+			},
+		},
+
+		{
+			name:    "dir",
+			mutable: true,
+			get: func(c *Config) string {
+				dir, err := os.Getwd()
+				if err != nil {
+					return c.Dir
+				}
+				return dir
+			},
+			set: func(c *Config, value string) error {
+				// Redis와 같이 작업 디렉터리를 옮겨 이후 파일 경로의 기준으로 삼습니다
+				if err := os.Chdir(value); err != nil {
+					return fmt.Errorf("%v", err)
+				}
+				c.Dir = value
+				return nil
+			},
+		},
+		// RDB 파일을 저장하지 않으므로 dbfilename은 설정 파일 값을 보여 주기만 합니다
+		stringParam("dbfilename", false, func(c *Config) *string { return &c.DBFilename }),
+
+		withAlias(intParam("list-max-listpack-size", true, -5, 1<<31-1, func(c *Config) *int { return &c.ListMaxListpackSize }), "list-max-ziplist-size"),
+		// 리스트 노드를 압축하지 않으므로 list-compress-depth도 보여 주기만 합니다
+		intParam("list-compress-depth", false, 0, 1<<31-1, func(c *Config) *int { return &c.ListCompressDepth }),
+		memoryParam("stream-node-max-bytes", true, func(c *Config) *int64 { return &c.StreamNodeMaxBytes }),
+		intParam("stream-node-max-entries", true, 0, 1<<31-1, func(c *Config) *int { return &c.StreamNodeMaxEntries }),
+	}
+
+	m := make(map[string]*param, len(list)*2)
+	for i := range list {
+		p := &list[i]
+		m[p.name] = p
+		if p.alias != "" {
+			m[p.alias] = p
+		}
+	}
+	return m
+}
+
+// withAlias는 list-max-ziplist-size 같은 예전 이름도 받아들이게 합니다
+func withAlias(p param, alias string) param {
+	p.alias = alias
+	return p
+}
+
+func lookupParam(name string) (*param, bool) {
+	p, ok := params[strings.ToLower(name)]
+	return p, ok
+}
+
+// Set은 설정 파일이나 명령줄에서 읽은 값을 반영합니다 (CONFIG SET과 달리 바꿀 수 없는 항목도 설정합니다)
+func (c *Config) Set(name, value string) error {
+	p, ok := lookupParam(name)
+	if !ok {
+		return fmt.Errorf("Bad directive or wrong number of arguments")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	return p.set(c, value)
+}
+
+// SetRuntime은 CONFIG SET name value [name value ...]을 처리합니다
+// 하나라도 실패하면 이미 바꾼 값을 되돌려 모두 적용하지 않습니다
+func (c *Config) SetRuntime(pairs [][2]string) error {
+	targets := make([]*param, len(pairs))
+	for i, pair := range pairs {
+		p, ok := lookupParam(pair[0])
+		if !ok {
+			return fmt.Errorf("Unknown option or number of arguments for CONFIG SET - '%s'", pair[0])
+		}
+		if !p.mutable {
+			return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - can't set immutable config", pair[0])
+		}
+		for _, other := range targets[:i] {
+			if other == p {
+				return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - duplicate parameter", pair[0])
+			}
+		}
+		targets[i] = p
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	previous := make([]string, len(pairs))
+	for i, p := range targets {
+		previous[i] = p.get(c)
+	}
+
+	for i, p := range targets {
+		if err := p.set(c, pairs[i][1]); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				_ = targets[j].set(c, previous[j])
+			}
+			return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - %v", pairs[i][0], err)
+		}
+	}
+
+	for _, p := range targets {
+		for _, hook := range c.hooks[p.name] {
+			hook(c)
+		}
+	}
+	return nil
+}
+
+// OnChange는 CONFIG SET으로 값이 바뀌었을 때 부를 함수를 등록합니다
+// 훅은 설정 잠금을 잡은 채로 불리므로 Config의 필드를 바로 읽으면 됩니다
+func (c *Config) OnChange(name string, hook func(c *Config)) {
+	c.Lock()
+	defer c.Unlock()
+	if c.hooks == nil {
+		c.hooks = make(map[string][]func(c *Config))
+	}
+	c.hooks[name] = append(c.hooks[name], hook)
+}
+
+// Get은 CONFIG GET처럼 glob 패턴에 맞는 항목의 이름과 값을 이름 순서로 반환합니다
+func (c *Config) Get(patterns ...string) [][2]string {
+	c.RLock()
+	defer c.RUnlock()
+
+	seen := make(map[*param]bool)
+	var result [][2]string
+	for _, pattern := range patterns {
+		for name, p := range params {
+			if seen[p] {
+				continue
+			}
+			// 별칭은 패턴이 아니라 정확히 그 이름으로 물었을 때만 보여 줍니다
+			if name == p.alias && !strings.EqualFold(pattern, name) {
+				continue
+			}
+			if glob.Match(pattern, name, true) {
+				seen[p] = true
+				result = append(result, [2]string{name, p.get(c)})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSEnabled는 TLS 리스너를 열어야 하는지 반환합니다
+func (c *Config) TLSEnabled() bool {
+	return c.TLSPort != 0
+}
+
+// ServerTLSConfig는 tls-port 리스너에 쓸 TLS 설정을 만듭니다
+// tls-auth-clients가 yes면 tls-ca-cert-file로 검증되는 클라이언트 인증서를 요구합니다
+func (c *Config) ServerTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch strings.ToLower(c.TLSAuthClients) {
+	case "no":
+		tlsConfig.ClientAuth = tls.NoClientCert
+	case "optional":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if tlsConfig.ClientAuth != tls.NoClientCert {
+		pool, err := c.loadCAPool()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// ClientTLSConfig는 tls-replication으로 마스터에 접속할 때 쓸 TLS 설정을 만듭니다
+// Redis와 같이 서버 인증서를 클라이언트 인증서로도 사용합니다
+func (c *Config) ClientTLSConfig(serverName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.TLSCertFile != "" && c.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.TLSCACertFile != "" {
+		pool, err := c.loadCAPool()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+func (c *Config) loadCAPool() (*x509.CertPool, error) {
+	if c.TLSCACertFile == "" {
+		return nil, fmt.Errorf("tls-ca-cert-file is required to authenticate TLS peers")
+	}
+	pem, err := os.ReadFile(c.TLSCACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid certificates in %s", c.TLSCACertFile)
+	}
+	return pool, nil
+}
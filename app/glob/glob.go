@@ -0,0 +1,123 @@
+package glob
+
+// Match는 Redis의 stringmatchlen과 같은 규칙으로 glob 패턴을 비교합니다
+// 지원하는 문법: * ? [abc] [^abc] [a-z] \x
+func Match(pattern, str string, nocase bool) bool {
+	skipLongerMatches := false
+	return match(pattern, str, nocase, &skipLongerMatches, 0)
+}
+
+// 너무 깊은 '*' 중첩으로 스택이 넘치지 않도록 막습니다
+const maxNesting = 1000
+
+func match(pattern, str string, nocase bool, skipLongerMatches *bool, nesting int) bool {
+	if nesting > maxNesting {
+		return false
+	}
+
+	for len(pattern) > 0 && len(str) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for len(str) > 0 {
+				if match(pattern[1:], str, nocase, skipLongerMatches, nesting+1) {
+					return true
+				}
+				if *skipLongerMatches {
+					// 뒤쪽 패턴이 이미 남은 문자열 전체에서 실패했으므로 더 긴 매칭도 실패합니다
+					return false
+				}
+				str = str[1:]
+			}
+			*skipLongerMatches = true
+			return false
+		case '?':
+			str = str[1:]
+		case '[':
+			pattern = pattern[1:]
+			not := len(pattern) > 0 && pattern[0] == '^'
+			if not {
+				pattern = pattern[1:]
+			}
+			matched := false
+			for {
+				if len(pattern) == 0 {
+					break
+				}
+				if pattern[0] == '\\' && len(pattern) >= 2 {
+					pattern = pattern[1:]
+					if pattern[0] == str[0] {
+						matched = true
+					}
+				} else if pattern[0] == ']' {
+					break
+				} else if len(pattern) >= 3 && pattern[1] == '-' {
+					start, end := pattern[0], pattern[2]
+					if start > end {
+						start, end = end, start
+					}
+					c := str[0]
+					if nocase {
+						start, end, c = lower(start), lower(end), lower(c)
+					}
+					pattern = pattern[2:]
+					if c >= start && c <= end {
+						matched = true
+					}
+				} else if equal(pattern[0], str[0], nocase) {
+					matched = true
+				}
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				// 닫는 ']'가 없으면 마지막 문자에서 멈춘 것으로 봅니다
+				pattern = " "
+			}
+			if not {
+				matched = !matched
+			}
+			if !matched {
+				return false
+			}
+			str = str[1:]
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if !equal(pattern[0], str[0], nocase) {
+				return false
+			}
+			str = str[1:]
+		}
+		pattern = pattern[1:]
+	}
+
+	// 빈 문자열은 '*'로만 이루어진 패턴과 매칭됩니다
+	if len(str) == 0 {
+		for len(pattern) > 0 && pattern[0] == '*' {
+			pattern = pattern[1:]
+		}
+	}
+	return len(pattern) == 0 && len(str) == 0
+}
+
+func equal(a, b byte, nocase bool) bool {
+	if nocase {
+		return lower(a) == lower(b)
+	}
+	return a == b
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,45 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, str string
+		nocase, want bool
+	}{
+		{"*", "", false, true},
+		{"*", "anything", false, true},
+		{"cache:*", "cache:user:1", false, true},
+		{"cache:*", "session:1", false, false},
+		{"h?llo", "hello", false, true},
+		{"h?llo", "hllo", false, false},
+		{"h[ae]llo", "hallo", false, true},
+		{"h[ae]llo", "hillo", false, false},
+		{"h[^e]llo", "hallo", false, true},
+		{"h[^e]llo", "hello", false, false},
+		{"h[a-b]llo", "hbllo", false, true},
+		{"h[b-a]llo", "hallo", false, true},
+		{"h\\*llo", "h*llo", false, true},
+		{"h\\*llo", "hello", false, false},
+		{"HELLO", "hello", true, true},
+		{"HELLO", "hello", false, false},
+		{"a*b*c", "axxbyyc", false, true},
+		{"a*b*c", "axxbyy", false, false},
+		{"[abc", "a", false, true},
+	} {
+		if got := Match(tc.pattern, tc.str, tc.nocase); got != tc.want {
+			t.Errorf("Match(%q, %q, %v) = %v, want %v", tc.pattern, tc.str, tc.nocase, got, tc.want)
+		}
+	}
+}
+
+func TestMatchPathological(t *testing.T) {
+	// 지수 시간이 걸리는 패턴도 빠르게 끝나야 합니다
+	pattern := strings.Repeat("a*", 50) + "b"
+	if Match(pattern, strings.Repeat("a", 100), false) {
+		t.Errorf("매칭되면 안 됨")
+	}
+}
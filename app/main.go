@@ -1,23 +1,25 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/server"
 )
 
 func main() {
-	port := flag.Int("port", 6379, "Port to listen on")
-	replicaOf := flag.String("replicaof", "", "Set server as replica")
-	flag.Parse()
+	cfg := config.Default()
 
-	address := fmt.Sprintf("0.0.0.0:%d", *port)
+	// redis-server와 같이 [설정 파일] [--이름 값 ...] 형식의 인수를 받습니다
+	if err := cfg.LoadArgs(os.Args[1:]); err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
 
-	newServer, err := server.NewServer(address, *replicaOf, *port)
+	newServer, err := server.NewServer(cfg)
 	if err != nil {
 		fmt.Printf("Failed to create server: %v\n", err)
 		os.Exit(1)
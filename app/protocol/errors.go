@@ -0,0 +1,17 @@
+package protocol
+
+import "fmt"
+
+// ProtocolError는 클라이언트가 보낸 요청이 프로토콜을 어겼을 때의 에러입니다
+// 서버는 "-ERR Protocol error: ..." 로 응답한 뒤 연결을 닫습니다
+type ProtocolError struct {
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.Message
+}
+
+func protocolError(format string, args ...any) error {
+	return &ProtocolError{Message: fmt.Sprintf(format, args...)}
+}
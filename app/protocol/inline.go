@@ -0,0 +1,135 @@
+package protocol
+
+var ErrUnbalancedQuotes = &ProtocolError{Message: "unbalanced quotes in request"}
+
+// SplitArgs는 redis.conf 한 줄처럼 인라인 명령어와 같은 규칙으로 나눠야 하는 문자열을 인수 목록으로 나눕니다
+func SplitArgs(line string) ([][]byte, error) {
+	return splitInlineArgs(line)
+}
+
+// splitInlineArgs는 인라인 명령어 한 줄을 Redis(sdssplitargs)와 같은 규칙으로 인수 목록으로 나눕니다
+// 큰따옴표 안에서는 \n, \r, \t, \b, \a, \\, \" 와 \xHH 이스케이프를, 작은따옴표 안에서는 \' 만 지원합니다
+func splitInlineArgs(line string) ([][]byte, error) {
+	args := make([][]byte, 0, 4)
+	i := 0
+
+	for {
+		// 앞쪽 공백 건너뛰기
+		for i < len(line) && isInlineSpace(line[i]) {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+
+		current := []byte{}
+		inDoubleQuotes := false
+		inSingleQuotes := false
+		done := false
+
+		for !done {
+			switch {
+			case inDoubleQuotes:
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					current = append(current, hexDigitToInt(line[i+2])*16+hexDigitToInt(line[i+3]))
+					i += 3
+				} else if c == '\\' && i+1 < len(line) {
+					i++
+					current = append(current, unescapeInline(line[i]))
+				} else if c == '"' {
+					// 닫는 따옴표 뒤에는 반드시 공백이나 줄 끝이 와야 합니다
+					if i+1 < len(line) && !isInlineSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current = append(current, c)
+				}
+
+			case inSingleQuotes:
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					current = append(current, '\'')
+				} else if c == '\'' {
+					if i+1 < len(line) && !isInlineSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current = append(current, c)
+				}
+
+			default:
+				if i >= len(line) {
+					done = true
+					break
+				}
+				switch c := line[i]; c {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inDoubleQuotes = true
+				case '\'':
+					inSingleQuotes = true
+				default:
+					current = append(current, c)
+				}
+			}
+
+			if i < len(line) {
+				i++
+			}
+		}
+
+		args = append(args, current)
+	}
+}
+
+func unescapeInline(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	default:
+		return c
+	}
+}
+
+func isInlineSpace(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t', '\v', '\f':
+		return true
+	default:
+		return false
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexDigitToInt(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,340 @@
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"math"
+)
+
+const (
+	defaultReaderSize = 16 * 1024
+	// 큰 명령어를 처리하느라 커진 버퍼는 비었을 때 기본 크기로 되돌립니다
+	maxIdleReaderSize = 1024 * 1024
+	// 줄바꿈 없이 이 크기를 넘는 인라인 명령어나 길이 헤더는 거부합니다
+	maxInlineSize = 64 * 1024
+	// 명령어 하나가 가질 수 있는 최대 인수 개수
+	MaxMultibulkLen = 1024 * 1024
+)
+
+// Limits는 클라이언트 요청 크기 제한입니다 (0이면 제한 없음)
+type Limits struct {
+	MaxBulkLen     int64 // proto-max-bulk-len
+	MaxQueryBuffer int64 // client-query-buffer-limit
+}
+
+// span은 버퍼 안에서 인수 하나가 차지하는 위치입니다 (Reader.r 기준 상대 위치)
+type span struct {
+	start, end int
+}
+
+// Reader는 연결마다 하나씩 두는 RESP 명령어 리더입니다
+// 읽은 바이트를 재사용하는 버퍼에 모아 두고, 인수는 복사 없이 버퍼의 슬라이스로 돌려줍니다
+type Reader struct {
+	rd  io.Reader
+	buf []byte
+	r   int // 아직 소비하지 않은 데이터의 시작 위치
+	w   int // 버퍼에 채워진 데이터의 끝 위치
+
+	// 명령어가 여러 번의 read에 걸쳐 도착할 때 이어서 파싱하기 위한 상태
+	pos       int // 다음에 파싱할 위치 (r 기준)
+	multibulk int // 현재 명령어의 배열 길이, 0이면 새 명령어를 기다리는 중
+	need      int // 파싱을 이어가는 데 필요한 최소 바이트 수 (r 기준)
+	inline    bool
+	complete  bool // 명령어 하나가 버퍼 안에 완성되어 있음
+	spans     []span
+
+	args [][]byte // 재사용하는 인수 배열
+
+	limits Limits
+}
+
+func NewReader(rd io.Reader) *Reader {
+	return &Reader{
+		rd:    rd,
+		buf:   make([]byte, defaultReaderSize),
+		spans: make([]span, 0, 8),
+		args:  make([][]byte, 0, 8),
+	}
+}
+
+// SetLimits는 이후에 읽는 명령어에 적용할 크기 제한을 설정합니다
+func (r *Reader) SetLimits(limits Limits) {
+	r.limits = limits
+}
+
+// Buffered는 아직 파싱하지 않고 버퍼에 남아 있는 바이트 수를 반환합니다
+func (r *Reader) Buffered() int {
+	return r.w - r.r
+}
+
+// ReadCommand는 명령어 하나를 읽어 인수 목록과 원본 RESP 바이트 길이를 반환합니다
+// 반환된 인수는 내부 버퍼를 가리키므로 다음 ReadCommand 호출 전까지만 유효합니다
+// 계속 보관해야 하는 값은 호출자가 복사해야 합니다
+func (r *Reader) ReadCommand() ([][]byte, int, error) {
+	for {
+		complete, err := r.scan()
+		if err != nil {
+			return nil, 0, err
+		}
+		if !complete {
+			if err := r.fill(); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		args, n, err := r.take()
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(args) == 0 {
+			// 빈 줄이나 길이 0인 배열은 무시합니다
+			continue
+		}
+		return args, n, nil
+	}
+}
+
+// HasCommand는 소켓에서 더 읽지 않고도 완성된 다음 명령어가 버퍼에 있는지 확인합니다
+// 파이프라인으로 들어온 명령어 묶음의 끝을 판단할 때 사용합니다
+// 직전에 받은 인수는 그대로 유효합니다
+func (r *Reader) HasCommand() bool {
+	for {
+		complete, err := r.scan()
+		if err != nil {
+			// 에러는 다음 ReadCommand에서 바로 반환됩니다
+			return true
+		}
+		if !complete {
+			return false
+		}
+		if !r.inline && len(r.spans) == 0 {
+			r.consume(r.pos)
+			continue
+		}
+		return true
+	}
+}
+
+// scan은 버퍼에 쌓인 데이터로 명령어 하나를 완성할 수 있는지 확인합니다
+// 데이터가 모자라면 false를 반환하고, 다음 호출에서 멈춘 위치부터 이어서 확인합니다
+func (r *Reader) scan() (bool, error) {
+	if r.complete {
+		return true, nil
+	}
+
+	data := r.buf[r.r:r.w]
+
+	if r.multibulk == 0 {
+		if len(data) == 0 {
+			return false, nil
+		}
+
+		line, next, found := readLine(data, 0)
+		if !found {
+			if len(data) > maxInlineSize {
+				if data[0] != byte(Array) {
+					return false, protocolError("too big inline request")
+				}
+				return false, protocolError("too big mbulk count string")
+			}
+			r.need = len(data) + 1
+			return false, nil
+		}
+
+		if data[0] != byte(Array) {
+			r.spans = r.spans[:0]
+			r.inline = len(bytes.TrimSpace(line)) > 0
+			r.pos = next
+			r.complete = true
+			return true, nil
+		}
+
+		count, valid := parseLength(line[1:])
+		if !valid || count > MaxMultibulkLen {
+			return false, protocolError("invalid multibulk length")
+		}
+
+		r.spans = r.spans[:0]
+		r.pos = next
+		if count <= 0 {
+			r.complete = true
+			return true, nil
+		}
+		r.multibulk = count
+	}
+
+	for len(r.spans) < r.multibulk {
+		if r.pos >= len(data) {
+			r.need = r.pos + 1
+			return false, nil
+		}
+		elemType := RespType(data[r.pos])
+		if elemType != BulkString && elemType != Integer && elemType != SimpleString {
+			return false, protocolError("expected '$', got '%c'", data[r.pos])
+		}
+
+		line, next, found := readLine(data, r.pos)
+		if !found {
+			if len(data)-r.pos > maxInlineSize {
+				return false, protocolError("too big bulk count string")
+			}
+			r.need = len(data) + 1
+			return false, nil
+		}
+
+		if elemType != BulkString {
+			// 기존 ReadRESP와 같이 정수나 단순 문자열 원소도 인수로 받아들입니다
+			r.spans = append(r.spans, span{start: r.pos + 1, end: r.pos + 1 + len(line) - 1})
+			r.pos = next
+			continue
+		}
+
+		size, valid := parseLength(line[1:])
+		if !valid || size < 0 || (r.limits.MaxBulkLen > 0 && int64(size) > r.limits.MaxBulkLen) {
+			return false, protocolError("invalid bulk length")
+		}
+
+		end := next + size
+		if end+2 > len(data) {
+			r.need = end + 2
+			return false, nil
+		}
+		if data[end] != '\r' || data[end+1] != '\n' {
+			return false, protocolError("expected CRLF after bulk string")
+		}
+
+		r.spans = append(r.spans, span{start: next, end: end})
+		r.pos = end + 2
+	}
+
+	r.complete = true
+	return true, nil
+}
+
+// take는 scan으로 완성한 명령어의 인수를 만들고 해당 바이트를 소비합니다
+func (r *Reader) take() ([][]byte, int, error) {
+	data := r.buf[r.r:r.w]
+	n := r.pos
+
+	if r.inline {
+		line, _, _ := readLine(data, 0)
+		args, err := splitInlineArgs(string(line))
+		if err != nil {
+			return nil, 0, err
+		}
+		r.consume(n)
+		return args, n, nil
+	}
+
+	// 용량을 잘라 둔 슬라이스로 인수를 만들어 append가 다음 인수를 덮어쓰지 않게 합니다
+	r.args = r.args[:0]
+	for _, s := range r.spans {
+		r.args = append(r.args, data[s.start:s.end:s.end])
+	}
+
+	r.consume(n)
+	return r.args, n, nil
+}
+
+// consume은 파싱을 마친 n 바이트를 버리고 파싱 상태를 초기화합니다
+func (r *Reader) consume(n int) {
+	r.r += n
+	r.pos = 0
+	r.multibulk = 0
+	r.need = 0
+	r.inline = false
+	r.complete = false
+}
+
+// fill은 버퍼를 앞으로 당기거나 키운 뒤 소켓에서 데이터를 더 읽습니다
+func (r *Reader) fill() error {
+	if r.r == r.w {
+		r.r, r.w = 0, 0
+		if len(r.buf) > maxIdleReaderSize {
+			r.buf = make([]byte, defaultReaderSize)
+		}
+	} else if r.r > 0 {
+		copy(r.buf, r.buf[r.r:r.w])
+		r.w -= r.r
+		r.r = 0
+	}
+
+	// 버퍼에는 아직 완성되지 않은 명령어 하나만 남아 있으므로, 그 크기가 곧 쿼리 버퍼 크기입니다
+	if limit := r.limits.MaxQueryBuffer; limit > 0 && (int64(r.need) > limit || int64(r.w) >= limit) {
+		return protocolError("client query buffer limit exceeded")
+	}
+
+	if r.w == len(r.buf) || r.need > len(r.buf) {
+		size := 2 * len(r.buf)
+		if limit := int(r.limits.MaxQueryBuffer); limit > 0 && size > limit {
+			size = limit
+		}
+		if size < r.need {
+			size = r.need
+		}
+		buf := make([]byte, size)
+		copy(buf, r.buf[:r.w])
+		r.buf = buf
+	}
+
+	end := len(r.buf)
+	if limit := int(r.limits.MaxQueryBuffer); limit > 0 && end > limit {
+		end = limit
+	}
+	n, err := r.rd.Read(r.buf[r.w:end])
+	r.w += n
+	if n > 0 {
+		return nil
+	}
+	if err == nil {
+		return io.ErrNoProgress
+	}
+	return err
+}
+
+// readLine은 off부터 시작하는 한 줄을 CRLF(또는 LF)를 제외하고 반환합니다
+func readLine(data []byte, off int) (line []byte, next int, found bool) {
+	idx := bytes.IndexByte(data[off:], '\n')
+	if idx < 0 {
+		return nil, 0, false
+	}
+	line = data[off : off+idx]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, off + idx + 1, true
+}
+
+// parseLength는 문자열 변환 없이 RESP 길이 값을 파싱합니다 (int를 넘치는 값은 잘못된 길이로 봅니다)
+func parseLength(b []byte) (int, bool) {
+	if len(b) == 0 || len(b) > 19 {
+		return 0, false
+	}
+
+	negative := false
+	if b[0] == '-' {
+		negative = true
+		b = b[1:]
+		if len(b) == 0 {
+			return 0, false
+		}
+	}
+
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := int(c - '0')
+		if n > (math.MaxInt-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+
+	if negative {
+		return -n, true
+	}
+	return n, true
+}
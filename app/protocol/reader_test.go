@@ -0,0 +1,215 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func readAll(t *testing.T, r *Reader) [][]string {
+	t.Helper()
+	var out [][]string
+	for {
+		args, _, err := r.ReadCommand()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("ReadCommand 실패: %v", err)
+		}
+		cmd := make([]string, len(args))
+		for i, arg := range args {
+			cmd[i] = string(arg)
+		}
+		out = append(out, cmd)
+	}
+}
+
+func TestReaderPipeline(t *testing.T) {
+	input := "*1\r\n$4\r\nPING\r\n*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$0\r\n\r\nECHO \"a b\"\r\n\r\n*2\r\n$3\r\nGET\r\n$1\r\nk\r\n"
+	want := [][]string{{"PING"}, {"SET", "k", ""}, {"ECHO", "a b"}, {"GET", "k"}}
+
+	for name, rd := range map[string]io.Reader{
+		"whole":   strings.NewReader(input),
+		"onebyte": iotest.OneByteReader(strings.NewReader(input)),
+	} {
+		got := readAll(t, NewReader(rd))
+		if len(got) != len(want) {
+			t.Fatalf("%s: 명령어 개수가 다름. got=%q, want=%q", name, got, want)
+		}
+		for i := range want {
+			if strings.Join(got[i], " ") != strings.Join(want[i], " ") || len(got[i]) != len(want[i]) {
+				t.Errorf("%s: %d번째 명령어가 다름. got=%q, want=%q", name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestReaderRawLen(t *testing.T) {
+	cmd := "*2\r\n$4\r\nECHO\r\n$5\r\nhello\r\n"
+	r := NewReader(strings.NewReader(cmd + cmd))
+	for i := 0; i < 2; i++ {
+		_, n, err := r.ReadCommand()
+		if err != nil {
+			t.Fatalf("ReadCommand 실패: %v", err)
+		}
+		if n != len(cmd) {
+			t.Errorf("원본 길이가 다름. got=%d, want=%d", n, len(cmd))
+		}
+	}
+}
+
+func TestReaderLargeBulk(t *testing.T) {
+	value := bytes.Repeat([]byte("x"), 3*defaultReaderSize+7)
+	input := "*2\r\n$4\r\nECHO\r\n$" + strconv.Itoa(len(value)) + "\r\n" + string(value) + "\r\n"
+
+	r := NewReader(iotest.HalfReader(strings.NewReader(input)))
+	args, _, err := r.ReadCommand()
+	if err != nil {
+		t.Fatalf("ReadCommand 실패: %v", err)
+	}
+	if !bytes.Equal(args[1], value) {
+		t.Errorf("큰 bulk string이 잘못 읽힘. len=%d, want=%d", len(args[1]), len(value))
+	}
+}
+
+func TestReaderArgsDoNotOverlap(t *testing.T) {
+	r := NewReader(strings.NewReader("*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n"))
+	args, _, err := r.ReadCommand()
+	if err != nil {
+		t.Fatalf("ReadCommand 실패: %v", err)
+	}
+	_ = append(args[0], 'X')
+	if string(args[1]) != "b" {
+		t.Errorf("append가 다음 인수를 덮어씀. got=%q", args[1])
+	}
+}
+
+func TestReaderHasCommand(t *testing.T) {
+	r := NewReader(strings.NewReader("*1\r\n$4\r\nPING\r\n\r\n*1\r\n$4\r\nPING\r\n*1\r\n$4\r\nPI"))
+	args, _, err := r.ReadCommand()
+	if err != nil {
+		t.Fatalf("ReadCommand 실패: %v", err)
+	}
+	if !r.HasCommand() {
+		t.Errorf("완성된 다음 명령어가 있어야 함")
+	}
+	if string(args[0]) != "PING" {
+		t.Errorf("HasCommand가 직전 인수를 덮어씀. got=%q", args[0])
+	}
+	if _, _, err := r.ReadCommand(); err != nil {
+		t.Fatalf("ReadCommand 실패: %v", err)
+	}
+	if r.HasCommand() {
+		t.Errorf("반만 도착한 명령어는 완성된 것으로 보면 안 됨")
+	}
+}
+
+func TestReaderMalformed(t *testing.T) {
+	for _, input := range []string{
+		"*x\r\n",
+		"*1\r\n*1\r\n",
+		"*1\r\n$-5\r\n",
+		"*1\r\n$3\r\nabcd\r\n",
+		"ECHO \"unbalanced\r\n",
+	} {
+		if _, _, err := NewReader(strings.NewReader(input)).ReadCommand(); err == nil || err == io.EOF {
+			t.Errorf("잘못된 입력 %q 에 대해 에러가 나야 함. got=%v", input, err)
+		}
+	}
+}
+
+func TestReaderLengthOverflow(t *testing.T) {
+	// int를 넘치는 길이가 음수로 바뀌어 빈 명령어나 제한 안의 길이로 읽히면 안 됩니다
+	for input, want := range map[string]string{
+		"*99999999999999999999\r\n":       "Protocol error: invalid multibulk length",
+		"*9999999999999999999\r\n":        "Protocol error: invalid multibulk length",
+		"*1\r\n$99999999999999999999\r\n": "Protocol error: invalid bulk length",
+		"*1\r\n$9999999999999999999\r\n":  "Protocol error: invalid bulk length",
+	} {
+		_, _, err := NewReader(strings.NewReader(input)).ReadCommand()
+		if err == nil || err.Error() != want {
+			t.Errorf("입력 %q 에 대한 에러가 다름. got=%v, want=%s", input, err, want)
+		}
+	}
+}
+
+func TestReaderLimits(t *testing.T) {
+	limits := Limits{MaxBulkLen: 16, MaxQueryBuffer: 64}
+	for input, want := range map[string]string{
+		"*1\r\n$999999999999\r\n": "Protocol error: invalid bulk length",
+		"*1\r\n$17\r\n":           "Protocol error: invalid bulk length",
+		"*2000000\r\n":            "Protocol error: invalid multibulk length",
+		"*5\r\n" + strings.Repeat("$10\r\n0123456789\r\n", 5): "Protocol error: client query buffer limit exceeded",
+	} {
+		r := NewReader(iotest.HalfReader(strings.NewReader(input)))
+		r.SetLimits(limits)
+		_, _, err := r.ReadCommand()
+		if err == nil || err.Error() != want {
+			t.Errorf("입력 %.20q 에 대한 에러가 다름. got=%v, want=%s", input, err, want)
+		}
+	}
+
+	r := NewReader(strings.NewReader(strings.Repeat("a", maxInlineSize+1)))
+	if _, _, err := r.ReadCommand(); err == nil || err.Error() != "Protocol error: too big inline request" {
+		t.Errorf("너무 긴 인라인 명령어는 거부되어야 함. got=%v", err)
+	}
+
+	r = NewReader(strings.NewReader("*2\r\n$4\r\nECHO\r\n$16\r\n0123456789abcdef\r\n"))
+	r.SetLimits(limits)
+	if _, _, err := r.ReadCommand(); err != nil {
+		t.Errorf("제한 안의 명령어는 읽혀야 함. got=%v", err)
+	}
+}
+
+// repeatReader는 같은 페이로드를 끝없이 반복해서 돌려줍니다
+type repeatReader struct {
+	data []byte
+	off  int
+}
+
+func (rr *repeatReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		c := copy(p[n:], rr.data[rr.off:])
+		n += c
+		rr.off = (rr.off + c) % len(rr.data)
+	}
+	return n, nil
+}
+
+var benchPayload = []byte("*3\r\n$3\r\nSET\r\n$8\r\nkey:1234\r\n$16\r\nvalue-0123456789\r\n")
+
+// BenchmarkReadRESP는 bufio.Reader 위에서 동작하는 ReadRESP 경로를 측정합니다
+func BenchmarkReadRESP(b *testing.B) {
+	reader := bufio.NewReader(&repeatReader{data: benchPayload})
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	for i := 0; i < b.N; i++ {
+		resp, err := ReadRESP(reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		args := make([][]byte, 0, resp.Length-1)
+		for _, arg := range resp.Arr[1:] {
+			args = append(args, arg.Data)
+		}
+		_ = args
+	}
+}
+
+// BenchmarkReaderReadCommand는 버퍼를 재사용하는 Reader 경로를 측정합니다
+func BenchmarkReaderReadCommand(b *testing.B) {
+	reader := NewReader(&repeatReader{data: benchPayload})
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	for i := 0; i < b.N; i++ {
+		if _, _, err := reader.ReadCommand(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -36,7 +36,8 @@ func ReadRESP(reader *bufio.Reader) (Resp, error) {
 
 	line = strings.TrimRight(line, "\r\n")
 	if len(line) == 0 {
-		return Resp{}, fmt.Errorf("empty line")
+		// 빈 인라인 명령어는 인수가 없는 배열로 취급합니다
+		return Resp{Type: Array, Length: 0, Raw: raw}, nil
 	}
 
 	respType := RespType(line[0])
@@ -80,7 +81,7 @@ func ReadRESP(reader *bufio.Reader) (Resp, error) {
 		if err != nil {
 			return Resp{}, err
 		}
-		if !strings.HasSuffix(string(buf), "\r\n") {
+		if buf[length] != '\r' || buf[length+1] != '\n' {
 			return Resp{}, fmt.Errorf("invalid response format")
 		}
 		data := buf[:length]
@@ -88,10 +89,24 @@ func ReadRESP(reader *bufio.Reader) (Resp, error) {
 		return Resp{Type: respType, Data: data, Raw: raw}, nil
 
 	default:
-		return Resp{}, fmt.Errorf("unknown RESP type: %c", respType)
+		return readInline(line, raw)
 	}
 }
 
+// readInline은 RESP 타입 바이트로 시작하지 않는 줄을 인라인 명령어로 해석해 배열로 반환합니다
+func readInline(line string, raw []byte) (Resp, error) {
+	args, err := splitInlineArgs(line)
+	if err != nil {
+		return Resp{}, err
+	}
+
+	arr := make([]Resp, 0, len(args))
+	for _, arg := range args {
+		arr = append(arr, Resp{Type: BulkString, Data: arg})
+	}
+	return Resp{Type: Array, Length: len(arr), Arr: arr, Raw: raw}, nil
+}
+
 func AppendString(buf []byte, data string) []byte {
 	buf = append(buf, '+')
 	buf = append(buf, data...)
@@ -0,0 +1,226 @@
+package pubsub
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/glob"
+	"github.com/codecrafters-io/redis-starter-go/app/protocol"
+)
+
+// Subscriber는 메시지를 받을 연결입니다 (*types.ConnContext)
+type Subscriber interface {
+	Write(message []byte) int
+	Flush() error
+}
+
+// PubSub은 채널과 패턴 구독 목록입니다
+// 키 공간 알림은 Store 잠금을 잡은 채 Publish하고 블로킹 명령어 고루틴에서도 부르므로 mu로 보호합니다
+type PubSub struct {
+	mu       sync.Mutex
+	channels map[string]map[Subscriber]struct{}
+	patterns map[string]map[Subscriber]struct{}
+
+	// 연결별로 구독 중인 채널과 패턴 (UNSUBSCRIBE 인수가 없을 때와 연결이 끊겼을 때 씁니다)
+	clientChannels map[Subscriber]map[string]struct{}
+	clientPatterns map[Subscriber]map[string]struct{}
+}
+
+func New() *PubSub {
+	return &PubSub{
+		channels:       make(map[string]map[Subscriber]struct{}),
+		patterns:       make(map[string]map[Subscriber]struct{}),
+		clientChannels: make(map[Subscriber]map[string]struct{}),
+		clientPatterns: make(map[Subscriber]map[string]struct{}),
+	}
+}
+
+// Subscribe는 channels를 구독하고 채널마다 subscribe 응답을 보냅니다 (SUBSCRIBE)
+// 반환값은 구독 중인 채널과 패턴 수입니다
+func (ps *PubSub) Subscribe(sub Subscriber, channels []string) (subs, psubs int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, channel := range channels {
+		add(ps.channels, ps.clientChannels, sub, channel)
+		sub.Write(reply("subscribe", &channel, ps.count(sub)))
+	}
+	return len(ps.clientChannels[sub]), len(ps.clientPatterns[sub])
+}
+
+// PSubscribe는 patterns를 구독하고 패턴마다 psubscribe 응답을 보냅니다 (PSUBSCRIBE)
+func (ps *PubSub) PSubscribe(sub Subscriber, patterns []string) (subs, psubs int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, pattern := range patterns {
+		add(ps.patterns, ps.clientPatterns, sub, pattern)
+		sub.Write(reply("psubscribe", &pattern, ps.count(sub)))
+	}
+	return len(ps.clientChannels[sub]), len(ps.clientPatterns[sub])
+}
+
+// Unsubscribe는 channels 구독을 풀고 채널마다 unsubscribe 응답을 보냅니다 (UNSUBSCRIBE)
+// channels가 비어 있으면 모든 채널 구독을 풀고, 구독 중인 채널이 없으면 채널 이름이 nil인 응답을 하나 보냅니다
+func (ps *PubSub) Unsubscribe(sub Subscriber, channels []string) (subs, psubs int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.unsubscribe(sub, channels, ps.channels, ps.clientChannels, "unsubscribe")
+	return len(ps.clientChannels[sub]), len(ps.clientPatterns[sub])
+}
+
+// PUnsubscribe는 patterns 구독을 풀고 패턴마다 punsubscribe 응답을 보냅니다 (PUNSUBSCRIBE)
+func (ps *PubSub) PUnsubscribe(sub Subscriber, patterns []string) (subs, psubs int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.unsubscribe(sub, patterns, ps.patterns, ps.clientPatterns, "punsubscribe")
+	return len(ps.clientChannels[sub]), len(ps.clientPatterns[sub])
+}
+
+// UnsubscribeAll은 연결이 끊길 때 응답 없이 모든 구독을 풉니다
+func (ps *PubSub) UnsubscribeAll(sub Subscriber) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for channel := range ps.clientChannels[sub] {
+		remove(ps.channels, ps.clientChannels, sub, channel)
+	}
+	for pattern := range ps.clientPatterns[sub] {
+		remove(ps.patterns, ps.clientPatterns, sub, pattern)
+	}
+}
+
+// unsubscribe는 Unsubscribe와 PUnsubscribe의 공통 부분입니다 (mu를 잡은 상태로 호출)
+func (ps *PubSub) unsubscribe(sub Subscriber, names []string, index map[string]map[Subscriber]struct{}, clientIndex map[Subscriber]map[string]struct{}, kind string) {
+	if len(names) == 0 {
+		names = sortedKeys(clientIndex[sub])
+		if len(names) == 0 {
+			sub.Write(reply(kind, nil, ps.count(sub)))
+			return
+		}
+	}
+	for _, name := range names {
+		remove(index, clientIndex, sub, name)
+		sub.Write(reply(kind, &name, ps.count(sub)))
+	}
+}
+
+// count는 sub가 구독 중인 채널과 패턴 수의 합입니다 (mu를 잡은 상태로 호출)
+func (ps *PubSub) count(sub Subscriber) int {
+	return len(ps.clientChannels[sub]) + len(ps.clientPatterns[sub])
+}
+
+// Publish는 channel을 구독하거나 channel에 맞는 패턴을 구독한 연결에 message를 보내고, 받은 연결 수를 반환합니다 (PUBLISH)
+// 구독자는 이벤트 루프가 아닌 다른 연결이므로 바로 Flush합니다
+func (ps *PubSub) Publish(channel, message string) int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	n := 0
+	if subs := ps.channels[channel]; len(subs) > 0 {
+		msg := protocol.AppendArray([]byte{}, 3)
+		msg = protocol.AppendBulkString(msg, []byte("message"))
+		msg = protocol.AppendBulkString(msg, []byte(channel))
+		msg = protocol.AppendBulkString(msg, []byte(message))
+		for sub := range subs {
+			sub.Write(msg)
+			_ = sub.Flush()
+			n++
+		}
+	}
+	for pattern, subs := range ps.patterns {
+		if !glob.Match(pattern, channel, false) {
+			continue
+		}
+		msg := protocol.AppendArray([]byte{}, 4)
+		msg = protocol.AppendBulkString(msg, []byte("pmessage"))
+		msg = protocol.AppendBulkString(msg, []byte(pattern))
+		msg = protocol.AppendBulkString(msg, []byte(channel))
+		msg = protocol.AppendBulkString(msg, []byte(message))
+		for sub := range subs {
+			sub.Write(msg)
+			_ = sub.Flush()
+			n++
+		}
+	}
+	return n
+}
+
+// Channels는 구독자가 있는 채널 중 pattern에 맞는 채널을 반환합니다 (PUBSUB CHANNELS, pattern이 빈 문자열이면 모두)
+func (ps *PubSub) Channels(pattern string) []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	channels := make([]string, 0)
+	for channel := range ps.channels {
+		if pattern == "" || glob.Match(pattern, channel, false) {
+			channels = append(channels, channel)
+		}
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// NumSub는 채널별 구독자 수입니다 (PUBSUB NUMSUB, 패턴 구독자는 세지 않습니다)
+func (ps *PubSub) NumSub(channels []string) []int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	counts := make([]int, len(channels))
+	for i, channel := range channels {
+		counts[i] = len(ps.channels[channel])
+	}
+	return counts
+}
+
+// NumPat은 구독 중인 서로 다른 패턴 수입니다 (PUBSUB NUMPAT)
+func (ps *PubSub) NumPat() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.patterns)
+}
+
+// Stats는 INFO stats의 pubsub_channels, pubsub_patterns입니다
+func (ps *PubSub) Stats() (channels, patterns int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.channels), len(ps.patterns)
+}
+
+func add(index map[string]map[Subscriber]struct{}, clientIndex map[Subscriber]map[string]struct{}, sub Subscriber, name string) {
+	if index[name] == nil {
+		index[name] = make(map[Subscriber]struct{})
+	}
+	index[name][sub] = struct{}{}
+	if clientIndex[sub] == nil {
+		clientIndex[sub] = make(map[string]struct{})
+	}
+	clientIndex[sub][name] = struct{}{}
+}
+
+func remove(index map[string]map[Subscriber]struct{}, clientIndex map[Subscriber]map[string]struct{}, sub Subscriber, name string) {
+	delete(index[name], sub)
+	if len(index[name]) == 0 {
+		delete(index, name)
+	}
+	delete(clientIndex[sub], name)
+	if len(clientIndex[sub]) == 0 {
+		delete(clientIndex, sub)
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reply는 [p]subscribe, [p]unsubscribe 응답입니다 (name이 nil이면 nil bulk string)
+func reply(kind string, name *string, count int) []byte {
+	msg := protocol.AppendArray([]byte{}, 3)
+	msg = protocol.AppendBulkString(msg, []byte(kind))
+	if name == nil {
+		msg = append(msg, protocol.AppendNilBulkString()...)
+	} else {
+		msg = protocol.AppendBulkString(msg, []byte(*name))
+	}
+	return protocol.AppendInt(msg, count)
+}
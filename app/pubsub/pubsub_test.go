@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recorder는 받은 응답을 모아 두는 Subscriber입니다
+type recorder struct {
+	out strings.Builder
+}
+
+func (r *recorder) Write(message []byte) int {
+	r.out.Write(message)
+	return len(message)
+}
+
+func (r *recorder) Flush() error { return nil }
+
+func TestPublish(t *testing.T) {
+	ps := New()
+	a, b := &recorder{}, &recorder{}
+
+	if subs, psubs := ps.Subscribe(a, []string{"news", "sport"}); subs != 2 || psubs != 0 {
+		t.Fatalf("구독 수가 다름. got=%d,%d", subs, psubs)
+	}
+	ps.PSubscribe(b, []string{"n*"})
+	a.out.Reset()
+	b.out.Reset()
+
+	if n := ps.Publish("news", "hi"); n != 2 {
+		t.Errorf("채널 구독자와 패턴 구독자가 모두 받아야 함. got=%d", n)
+	}
+	if got := a.out.String(); got != "*3\r\n$7\r\nmessage\r\n$4\r\nnews\r\n$2\r\nhi\r\n" {
+		t.Errorf("message 형식이 다름. got=%q", got)
+	}
+	if got := b.out.String(); got != "*4\r\n$8\r\npmessage\r\n$2\r\nn*\r\n$4\r\nnews\r\n$2\r\nhi\r\n" {
+		t.Errorf("pmessage 형식이 다름. got=%q", got)
+	}
+	if n := ps.Publish("weather", "rain"); n != 0 {
+		t.Errorf("구독자가 없으면 0이어야 함. got=%d", n)
+	}
+
+	if got := ps.Channels(""); !slices.Equal(got, []string{"news", "sport"}) {
+		t.Errorf("Channels가 다름. got=%q", got)
+	}
+	if got := ps.NumSub([]string{"news", "none"}); !slices.Equal(got, []int{1, 0}) {
+		t.Errorf("NumSub가 다름. got=%v", got)
+	}
+
+	a.out.Reset()
+	if subs, _ := ps.Unsubscribe(a, nil); subs != 0 {
+		t.Errorf("인수 없는 Unsubscribe는 모두 풀어야 함. got=%d", subs)
+	}
+	if got := a.out.String(); got != "*3\r\n$11\r\nunsubscribe\r\n$4\r\nnews\r\n:1\r\n*3\r\n$11\r\nunsubscribe\r\n$5\r\nsport\r\n:0\r\n" {
+		t.Errorf("unsubscribe 응답이 다름. got=%q", got)
+	}
+	a.out.Reset()
+	ps.Unsubscribe(a, nil)
+	if got := a.out.String(); got != "*3\r\n$11\r\nunsubscribe\r\n$-1\r\n:0\r\n" {
+		t.Errorf("구독이 없을 때 unsubscribe 응답이 다름. got=%q", got)
+	}
+
+	ps.UnsubscribeAll(b)
+	if channels, patterns := ps.Stats(); channels != 0 || patterns != 0 {
+		t.Errorf("모든 구독이 풀려야 함. got=%d,%d", channels, patterns)
+	}
+}
@@ -0,0 +1,12 @@
+package rdb
+
+import "hash/crc64"
+
+// crcTable은 Redis가 RDB와 DUMP에 쓰는 CRC-64/Jones 표입니다 (반사 다항식 0xad93d23594c935a9)
+var crcTable = crc64.MakeTable(0x95ac9329ac4bc9b5)
+
+// checksum은 Redis crc64(0, data)와 같은 값입니다
+// hash/crc64는 처음과 끝에 비트를 뒤집으므로 초기값을 뒤집어 넘기고 결과를 다시 뒤집습니다
+func checksum(data []byte) uint64 {
+	return ^crc64.Update(^uint64(0), crcTable, data)
+}
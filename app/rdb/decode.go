@@ -0,0 +1,188 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// ErrBadFormat은 RDB 값을 해석할 수 없을 때의 에러입니다
+var ErrBadFormat = errors.New("ERR Bad data format")
+
+// decoder는 RDB 값 인코딩을 앞에서부터 읽습니다
+type decoder struct {
+	buf    []byte
+	pos    int
+	limits Limits
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if d.pos >= len(d.buf) {
+		return 0, ErrBadFormat
+	}
+	b := d.buf[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *decoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, ErrBadFormat
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// readLenOrEncoding은 길이를 읽습니다. 앞 2비트가 11이면 encoded가 true이고 n은 특수 인코딩 종류입니다 (rdbLoadLenByRef)
+func (d *decoder) readLenOrEncoding() (n uint64, encoded bool, err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, false, err
+	}
+	switch b >> 6 {
+	case len6Bit:
+		return uint64(b & 0x3f), false, nil
+	case len14Bit:
+		next, err := d.readByte()
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b&0x3f)<<8 | uint64(next), false, nil
+	case encVal:
+		return uint64(b & 0x3f), true, nil
+	}
+	switch b {
+	case len32Bit:
+		p, err := d.readBytes(4)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(binary.BigEndian.Uint32(p)), false, nil
+	case len64Bit:
+		p, err := d.readBytes(8)
+		if err != nil {
+			return 0, false, err
+		}
+		return binary.BigEndian.Uint64(p), false, nil
+	}
+	return 0, false, ErrBadFormat
+}
+
+func (d *decoder) readLen() (uint64, error) {
+	n, encoded, err := d.readLenOrEncoding()
+	if err == nil && encoded {
+		err = ErrBadFormat
+	}
+	return n, err
+}
+
+// readString은 정수 인코딩과 LZF 압축을 풀어 문자열을 읽습니다 (rdbGenericLoadStringObject)
+func (d *decoder) readString() (string, error) {
+	n, encoded, err := d.readLenOrEncoding()
+	if err != nil {
+		return "", err
+	}
+	if !encoded {
+		p, err := d.readBytes(n)
+		return string(p), err
+	}
+
+	switch n {
+	case encInt8:
+		b, err := d.readByte()
+		return strconv.Itoa(int(int8(b))), err
+	case encInt16:
+		p, err := d.readBytes(2)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int16(binary.LittleEndian.Uint16(p)))), nil
+	case encInt32:
+		p, err := d.readBytes(4)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int32(binary.LittleEndian.Uint32(p)))), nil
+	case encLZF:
+		clen, err := d.readLen()
+		if err != nil {
+			return "", err
+		}
+		size, err := d.readLen()
+		if err != nil {
+			return "", err
+		}
+		compressed, err := d.readBytes(clen)
+		if err != nil {
+			return "", err
+		}
+		// 풀린 크기는 페이로드가 적은 값이라 믿지 않고, 메모리를 잡기 전에 압축 본문으로 나올 수 있는 크기인지 확인합니다
+		if size > uint64(len(compressed))*lzfMaxExpansion || (d.limits.MaxBulkLen > 0 && size > uint64(d.limits.MaxBulkLen)) {
+			return "", ErrBadFormat
+		}
+		out, err := lzfDecompress(compressed, size)
+		return string(out), err
+	}
+	return "", ErrBadFormat
+}
+
+// readUint은 스트림 ID처럼 길이 인코딩으로 저장한 숫자를 읽습니다
+func (d *decoder) readUint() (int, error) {
+	n, err := d.readLen()
+	return int(n), err
+}
+
+// lzfMaxExpansion은 LZF 압축 본문 1바이트가 풀리며 늘어날 수 있는 최대 배수입니다
+// 가장 긴 역참조는 3바이트로 264바이트를 만듭니다
+const lzfMaxExpansion = 88
+
+// lzfDecompress는 Redis가 rdbcompression으로 압축한 문자열을 풉니다 (lzf_decompress)
+func lzfDecompress(in []byte, size uint64) ([]byte, error) {
+	out := make([]byte, 0, size)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < 1<<5 {
+			// 리터럴 ctrl+1바이트
+			n := ctrl + 1
+			if i+n > len(in) {
+				return nil, ErrBadFormat
+			}
+			if uint64(len(out)+n) > size {
+				return nil, ErrBadFormat
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		// 앞에서 나온 바이트를 가리키는 역참조
+		n := ctrl >> 5
+		if n == 7 {
+			if i >= len(in) {
+				return nil, ErrBadFormat
+			}
+			n += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, ErrBadFormat
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, ErrBadFormat
+		}
+		if uint64(len(out)+n+2) > size {
+			return nil, ErrBadFormat
+		}
+		for j := 0; j < n+2; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if uint64(len(out)) != size {
+		return nil, ErrBadFormat
+	}
+	return out, nil
+}
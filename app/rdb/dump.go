@@ -0,0 +1,52 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
+)
+
+// ErrBadPayload는 RESTORE 페이로드의 버전이 더 높거나 체크섬이 맞지 않을 때의 에러입니다
+var ErrBadPayload = errors.New("ERR DUMP payload version or checksum are wrong")
+
+// footerSize는 페이로드 끝의 RDB 버전(2바이트)과 CRC64(8바이트)입니다
+const footerSize = 10
+
+// Dump는 값을 DUMP 페이로드로 직렬화합니다 (타입, RDB 값 인코딩, 버전, 체크섬, Redis createDumpPayload)
+// 리스트와 스트림은 limits에 맞춰 노드로 나눕니다. 알 수 없는 타입이면 false를 반환합니다
+func Dump(value entity.Entity, limits Limits) ([]byte, bool) {
+	e := &encoder{limits: limits}
+	if !e.writeValue(value) {
+		return nil, false
+	}
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, Version)
+	return binary.LittleEndian.AppendUint64(e.buf, checksum(e.buf)), true
+}
+
+// Restore는 DUMP 페이로드를 값으로 되돌립니다 (리스트는 limits의 list-max-listpack-size로 다시 나눕니다)
+// 이 서버보다 높은 RDB 버전이거나 체크섬이 다르면 ErrBadPayload, 본문을 해석할 수 없으면 ErrBadFormat을 반환합니다
+func Restore(payload []byte, limits Limits) (entity.Entity, error) {
+	if len(payload) < footerSize+1 {
+		return nil, ErrBadPayload
+	}
+	body := payload[:len(payload)-8]
+	version := binary.LittleEndian.Uint16(payload[len(payload)-footerSize:])
+	if version > Version || binary.LittleEndian.Uint64(payload[len(payload)-8:]) != checksum(body) {
+		return nil, ErrBadPayload
+	}
+
+	d := &decoder{buf: body[:len(body)-2], limits: limits}
+	typ, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	value, err := d.readValue(typ)
+	if err != nil {
+		return nil, ErrBadFormat
+	}
+	if d.pos != len(d.buf) {
+		return nil, ErrBadFormat
+	}
+	return value, nil
+}
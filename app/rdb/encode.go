@@ -0,0 +1,87 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// RDB 값 타입 (Redis rdb.h)
+const (
+	TypeString           = 0
+	TypeList             = 1
+	TypeListQuicklist2   = 18
+	TypeStreamListpacks  = 15
+	TypeStreamListpacks2 = 19
+	TypeStreamListpacks3 = 21
+)
+
+// Version은 DUMP 페이로드 끝에 적는 RDB 버전입니다 (Redis 7.2, 스트림은 STREAM_LISTPACKS_3)
+const Version = 11
+
+// 길이 인코딩의 앞 2비트 (Redis RDB_6BITLEN 등)
+const (
+	len6Bit  = 0
+	len14Bit = 1
+	len32Bit = 0x80
+	len64Bit = 0x81
+	encVal   = 3 // 뒤의 6비트가 특수 인코딩 종류입니다
+
+	encInt8  = 0
+	encInt16 = 1
+	encInt32 = 2
+	encLZF   = 3
+)
+
+// encoder는 RDB 값 인코딩을 버퍼에 씁니다
+type encoder struct {
+	buf    []byte
+	limits Limits
+}
+
+// writeLen은 RDB 길이 인코딩으로 n을 씁니다 (rdbSaveLen)
+func (e *encoder) writeLen(n uint64) {
+	switch {
+	case n < 1<<6:
+		e.buf = append(e.buf, byte(n)|len6Bit<<6)
+	case n < 1<<14:
+		e.buf = append(e.buf, byte(n>>8)|len14Bit<<6, byte(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, len32Bit)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	default:
+		e.buf = append(e.buf, len64Bit)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, n)
+	}
+}
+
+// writeString은 문자열을 씁니다. 32비트 정수로 표현되는 짧은 문자열은 정수 인코딩으로 줄입니다 (rdbSaveRawString)
+// 압축(LZF)은 하지 않습니다. 읽을 때는 압축된 문자열도 받습니다
+func (e *encoder) writeString(s string) {
+	if len(s) <= 11 {
+		if v, ok := canonicalInt(s); ok && v >= math.MinInt32 && v <= math.MaxInt32 {
+			switch {
+			case v >= math.MinInt8 && v <= math.MaxInt8:
+				e.buf = append(e.buf, encVal<<6|encInt8, byte(int8(v)))
+			case v >= math.MinInt16 && v <= math.MaxInt16:
+				e.buf = append(e.buf, encVal<<6|encInt16)
+				e.buf = binary.LittleEndian.AppendUint16(e.buf, uint16(int16(v)))
+			default:
+				e.buf = append(e.buf, encVal<<6|encInt32)
+				e.buf = binary.LittleEndian.AppendUint32(e.buf, uint32(int32(v)))
+			}
+			return
+		}
+	}
+	e.writeLen(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// canonicalInt는 s가 앞자리 0이나 + 기호 없이 쓴 int64인지 확인합니다 (다시 문자열로 바꿨을 때 같아야 정수로 저장합니다)
+func canonicalInt(s string) (int64, bool) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || strconv.FormatInt(v, 10) != s {
+		return 0, false
+	}
+	return v, true
+}
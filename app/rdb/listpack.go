@@ -0,0 +1,200 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+)
+
+// Redis listpack 형식입니다 (RDB의 리스트 노드와 스트림 노드가 이 형식으로 들어갑니다)
+// 메모리 안의 list.Listpack과는 엔트리 인코딩이 달라서 RDB를 읽고 쓸 때만 따로 만듭니다
+const (
+	lpHeaderSize = 6 // 전체 바이트 수(4) + 원소 수(2)
+	lpEOF        = 0xff
+)
+
+var errBadListpack = errors.New("bad listpack")
+
+// buildListpack은 values로 listpack을 만듭니다. 정수로 표현되는 값은 정수 인코딩으로 씁니다 (lpAppend)
+func buildListpack(values []string) []byte {
+	buf := make([]byte, lpHeaderSize, lpHeaderSize+len(values)*4)
+	for _, v := range values {
+		buf = appendLpEntry(buf, v)
+	}
+	buf = append(buf, lpEOF)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(len(buf)))
+	count := uint16(math.MaxUint16) // 65535 이상이면 Redis와 같이 "모름"으로 둡니다
+	if len(values) < math.MaxUint16 {
+		count = uint16(len(values))
+	}
+	binary.LittleEndian.PutUint16(buf[4:], count)
+	return buf
+}
+
+// appendLpEntry는 v를 엔트리 하나로 씁니다 (인코딩, 본문, backlen)
+func appendLpEntry(buf []byte, v string) []byte {
+	start := len(buf)
+	if n, ok := canonicalInt(v); ok {
+		buf = appendLpInt(buf, n)
+	} else {
+		buf = appendLpString(buf, v)
+	}
+	return appendBacklen(buf, len(buf)-start)
+}
+
+func appendLpInt(buf []byte, v int64) []byte {
+	switch {
+	case v >= 0 && v <= 127:
+		return append(buf, byte(v))
+	case v >= -4096 && v <= 4095:
+		u := uint16(v) & 0x1fff
+		return append(buf, 0xc0|byte(u>>8), byte(u))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return binary.LittleEndian.AppendUint16(append(buf, 0xf1), uint16(v))
+	case v >= -1<<23 && v < 1<<23:
+		u := uint32(v)
+		return append(buf, 0xf2, byte(u), byte(u>>8), byte(u>>16))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return binary.LittleEndian.AppendUint32(append(buf, 0xf3), uint32(v))
+	default:
+		return binary.LittleEndian.AppendUint64(append(buf, 0xf4), uint64(v))
+	}
+}
+
+func appendLpString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 64:
+		buf = append(buf, 0x80|byte(n))
+	case n < 4096:
+		buf = append(buf, 0xe0|byte(n>>8), byte(n))
+	default:
+		buf = binary.LittleEndian.AppendUint32(append(buf, 0xf0), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendBacklen은 엔트리 길이를 뒤에서부터 읽을 수 있게 씁니다 (오른쪽 바이트부터 7비트씩, 이어지면 최상위 비트를 켭니다)
+func appendBacklen(buf []byte, n int) []byte {
+	size := backlenSize(n)
+	for i := size - 1; i >= 0; i-- {
+		b := byte(n>>(7*i)) & 0x7f
+		if i < size-1 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+	}
+	return buf
+}
+
+func backlenSize(n int) int {
+	switch {
+	case n < 1<<7:
+		return 1
+	case n < 1<<14:
+		return 2
+	case n < 1<<21:
+		return 3
+	case n < 1<<28:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// parseListpack은 listpack의 원소를 모두 문자열로 읽습니다 (정수 엔트리는 10진수 문자열로 바꿉니다)
+func parseListpack(lp []byte) ([]string, error) {
+	if len(lp) < lpHeaderSize+1 || int(binary.LittleEndian.Uint32(lp)) != len(lp) || lp[len(lp)-1] != lpEOF {
+		return nil, errBadListpack
+	}
+	var values []string
+	pos := lpHeaderSize
+	for lp[pos] != lpEOF {
+		value, size, err := lpEntry(lp[pos : len(lp)-1])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		pos += size + backlenSize(size)
+		if pos >= len(lp) {
+			return nil, errBadListpack
+		}
+	}
+	if count := binary.LittleEndian.Uint16(lp[4:]); count != math.MaxUint16 && int(count) != len(values) {
+		return nil, errBadListpack
+	}
+	return values, nil
+}
+
+// lpEntry는 엔트리 하나의 값과 backlen을 뺀 길이를 반환합니다
+func lpEntry(p []byte) (string, int, error) {
+	need := func(n int) error {
+		if len(p) < n {
+			return errBadListpack
+		}
+		return nil
+	}
+	b := p[0]
+	switch {
+	case b&0x80 == 0: // 7비트 부호 없는 정수
+		return strconv.Itoa(int(b)), 1, nil
+	case b&0xc0 == 0x80: // 6비트 길이 문자열
+		n := int(b & 0x3f)
+		if err := need(1 + n); err != nil {
+			return "", 0, err
+		}
+		return string(p[1 : 1+n]), 1 + n, nil
+	case b&0xe0 == 0xc0: // 13비트 정수
+		if err := need(2); err != nil {
+			return "", 0, err
+		}
+		v := int64(uint16(b&0x1f)<<8 | uint16(p[1]))
+		if v >= 1<<12 {
+			v -= 1 << 13
+		}
+		return strconv.FormatInt(v, 10), 2, nil
+	case b&0xf0 == 0xe0: // 12비트 길이 문자열
+		if err := need(2); err != nil {
+			return "", 0, err
+		}
+		n := int(b&0x0f)<<8 | int(p[1])
+		if err := need(2 + n); err != nil {
+			return "", 0, err
+		}
+		return string(p[2 : 2+n]), 2 + n, nil
+	}
+
+	switch b {
+	case 0xf0: // 32비트 길이 문자열
+		if err := need(5); err != nil {
+			return "", 0, err
+		}
+		n := int(binary.LittleEndian.Uint32(p[1:]))
+		if err := need(5 + n); err != nil {
+			return "", 0, err
+		}
+		return string(p[5 : 5+n]), 5 + n, nil
+	case 0xf1:
+		if err := need(3); err != nil {
+			return "", 0, err
+		}
+		return strconv.FormatInt(int64(int16(binary.LittleEndian.Uint16(p[1:]))), 10), 3, nil
+	case 0xf2:
+		if err := need(4); err != nil {
+			return "", 0, err
+		}
+		v := int64(int32(uint32(p[1])<<8|uint32(p[2])<<16|uint32(p[3])<<24) >> 8)
+		return strconv.FormatInt(v, 10), 4, nil
+	case 0xf3:
+		if err := need(5); err != nil {
+			return "", 0, err
+		}
+		return strconv.FormatInt(int64(int32(binary.LittleEndian.Uint32(p[1:]))), 10), 5, nil
+	case 0xf4:
+		if err := need(9); err != nil {
+			return "", 0, err
+		}
+		return strconv.FormatInt(int64(binary.LittleEndian.Uint64(p[1:])), 10), 9, nil
+	}
+	return "", 0, errBadListpack
+}
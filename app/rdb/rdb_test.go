@@ -0,0 +1,174 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity/list"
+)
+
+func TestChecksum(t *testing.T) {
+	// Redis crc64 테스트 벡터
+	if got := checksum([]byte("123456789")); got != 0xe9c6d914c4b8d9ca {
+		t.Errorf("checksum = %x", got)
+	}
+}
+
+func TestRestoreRedisPayload(t *testing.T) {
+	// Redis 문서의 DUMP 예 (SET mykey 10, RDB 버전 9)
+	value, err := Restore([]byte("\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n"), DefaultLimits)
+	if err != nil {
+		t.Fatalf("Restore 실패: %v", err)
+	}
+	if s, ok := value.(*entity.StringEntity); !ok || s.ValueData != "10" {
+		t.Errorf("값이 다름. got=%#v", value)
+	}
+	if _, err := Restore([]byte("\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\x0b"), DefaultLimits); err != ErrBadPayload {
+		t.Errorf("체크섬이 틀리면 거부해야 함. got=%v", err)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	l := entity.NewListEntity(list.DefaultFill)
+	for i := 0; i < 3000; i++ {
+		// 정수와 문자열, 여러 노드에 걸치는 크기를 섞습니다
+		l.ValueData.RPush([][]byte{[]byte(strconv.Itoa(i*997 - 100000)), []byte(strings.Repeat("v", i%70))})
+	}
+
+	stream := entity.NewStreamEntity()
+	for i := 1; i <= 250; i++ {
+		fields := []entity.FieldValue{{Key: "temp", Value: strconv.Itoa(i)}, {Key: "city", Value: "seoul"}}
+		if i%7 == 0 {
+			fields = []entity.FieldValue{{Key: "other", Value: "x"}}
+		}
+		stream.Entries = append(stream.Entries, entity.StreamEntry{Id: &entity.StreamId{Millis: 1000 + i/3, Seq: i % 3}, Fields: fields})
+	}
+	stream.LastMillis, stream.LastSeq = 1000+250/3, 250%3
+	// 지운 엔트리가 있었던 것처럼 엔트리 수보다 크게 둡니다
+	stream.EntriesAdded = 300
+
+	for _, value := range []entity.Entity{
+		&entity.StringEntity{ValueData: "hello"},
+		&entity.StringEntity{ValueData: "-32768"},
+		&entity.StringEntity{ValueData: "007"},
+		&entity.StringEntity{ValueData: strings.Repeat("x", 20000)},
+		l,
+		stream,
+	} {
+		payload, ok := Dump(value, DefaultLimits)
+		if !ok {
+			t.Fatalf("Dump 실패: %T", value)
+		}
+		got, err := Restore(payload, DefaultLimits)
+		if err != nil {
+			t.Fatalf("Restore 실패 (%T): %v", value, err)
+		}
+		if l, ok := value.(*entity.ListEntity); ok {
+			if !reflect.DeepEqual(got.(*entity.ListEntity).ValueData.LRange(0, -1), l.ValueData.LRange(0, -1)) {
+				t.Errorf("리스트가 다름")
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("값이 다름 (%T)", value)
+		}
+	}
+}
+
+func TestDumpLimits(t *testing.T) {
+	l := entity.NewListEntity(list.DefaultFill)
+	for i := 0; i < 10; i++ {
+		l.ValueData.RPush([][]byte{[]byte("v" + strconv.Itoa(i))})
+	}
+	stream := entity.NewStreamEntity()
+	for i := 1; i <= 30; i++ {
+		stream.Entries = append(stream.Entries, entity.StreamEntry{Id: &entity.StreamId{Millis: i}, Fields: []entity.FieldValue{{Key: "f", Value: strings.Repeat("x", 100)}}})
+	}
+	stream.LastMillis, stream.EntriesAdded = 30, 30
+
+	// 타입 바로 뒤의 길이가 노드 수입니다
+	for _, tc := range []struct {
+		value  entity.Entity
+		limits Limits
+		nodes  byte
+	}{
+		{l, DefaultLimits, 1},
+		{l, Limits{ListMaxListpackSize: 4}, 3},
+		{stream, DefaultLimits, 1},
+		{stream, Limits{StreamNodeMaxEntries: 7}, 5},
+		{stream, Limits{StreamNodeMaxBytes: 1024}, 4},
+		{stream, Limits{}, 1},
+	} {
+		payload, _ := Dump(tc.value, tc.limits)
+		if payload[1] != tc.nodes {
+			t.Errorf("%T %+v의 노드 수가 다름. got=%d want=%d", tc.value, tc.limits, payload[1], tc.nodes)
+		}
+		if _, err := Restore(payload, tc.limits); err != nil {
+			t.Errorf("%T %+v를 되돌리지 못함: %v", tc.value, tc.limits, err)
+		}
+	}
+}
+
+func TestRestoreStreamIds(t *testing.T) {
+	entry := func(ms int) entity.StreamEntry {
+		return entity.StreamEntry{Id: &entity.StreamId{Millis: ms}, Fields: []entity.FieldValue{{Key: "f", Value: "v"}}}
+	}
+	for name, s := range map[string]*entity.StreamEntity{
+		"순서가 뒤바뀐 ID":     {Entries: []entity.StreamEntry{entry(2), entry(1)}, LastMillis: 2},
+		"같은 ID":          {Entries: []entity.StreamEntry{entry(1), entry(1)}, LastMillis: 1},
+		"last_id보다 큰 ID": {Entries: []entity.StreamEntry{entry(1), entry(5)}, LastMillis: 3},
+	} {
+		payload, _ := Dump(s, DefaultLimits)
+		if _, err := Restore(payload, DefaultLimits); err != ErrBadFormat {
+			t.Errorf("%s는 거부해야 함. got=%v", name, err)
+		}
+	}
+}
+
+func TestRestoreHugeLZFSize(t *testing.T) {
+	// 체크섬은 맞지만 풀린 크기를 1<<62로 적은 LZF 문자열은 메모리를 잡기 전에 거부해야 합니다
+	e := &encoder{buf: []byte{TypeString, encVal<<6 | encLZF}}
+	e.writeLen(5)
+	e.writeLen(1 << 62)
+	e.buf = append(e.buf, 0x00, 'a', 0xe0, 0x00, 0x00)
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, Version)
+	payload := binary.LittleEndian.AppendUint64(e.buf, checksum(e.buf))
+	if _, err := Restore(payload, DefaultLimits); err != ErrBadFormat {
+		t.Errorf("너무 큰 LZF 크기는 거부해야 함. got=%v", err)
+	}
+
+	// proto-max-bulk-len보다 크게 풀리는 문자열도 거부합니다
+	limits := DefaultLimits
+	limits.MaxBulkLen = 5
+	d := &decoder{buf: []byte{encVal<<6 | encLZF, 5, 10, 0x00, 'a', 0xe0, 0x00, 0x00}, limits: limits}
+	if _, err := d.readString(); err != ErrBadFormat {
+		t.Errorf("proto-max-bulk-len보다 큰 문자열은 거부해야 함. got=%v", err)
+	}
+}
+
+func TestListpackInts(t *testing.T) {
+	values := []string{"0", "127", "128", "-1", "-4096", "4095", "-32768", "32767", "8388607", "-8388608", "2147483647", "-9223372036854775808", "01", "+1", ""}
+	got, err := parseListpack(buildListpack(values))
+	if err != nil {
+		t.Fatalf("parseListpack 실패: %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("값이 다름. got=%q", got)
+	}
+}
+
+func TestLZF(t *testing.T) {
+	// 리터럴 "a" 뒤에 바로 앞 바이트를 9번 복사하는 역참조
+	got, err := lzfDecompress([]byte{0x00, 'a', 0xe0, 0x00, 0x00}, 10)
+	if err != nil || string(got) != "aaaaaaaaaa" {
+		t.Errorf("lzfDecompress = %q, %v", got, err)
+	}
+	d := &decoder{buf: []byte{encVal<<6 | encLZF, 5, 10, 0x00, 'a', 0xe0, 0x00, 0x00}}
+	if s, err := d.readString(); err != nil || s != "aaaaaaaaaa" {
+		t.Errorf("압축 문자열 = %q, %v", s, err)
+	}
+}
@@ -0,0 +1,393 @@
+package rdb
+
+import (
+	"encoding/binary"
+	"slices"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity/list"
+)
+
+const (
+	// 퀵리스트 노드 컨테이너 (Redis QUICKLIST_NODE_CONTAINER_*)
+	containerPlain  = 1
+	containerPacked = 2
+
+	// 스트림 엔트리 플래그 (Redis STREAM_ITEM_FLAG_*)
+	streamItemDeleted    = 1
+	streamItemSameFields = 2
+)
+
+// Limits는 리스트와 스트림을 노드로 나누는 기준과 RESTORE가 받을 문자열 크기입니다
+// (list-max-listpack-size, stream-node-max-bytes, stream-node-max-entries, proto-max-bulk-len)
+type Limits struct {
+	ListMaxListpackSize  int
+	StreamNodeMaxBytes   int64 // 0이면 크기로 나누지 않습니다
+	StreamNodeMaxEntries int   // 0이면 엔트리 수로 나누지 않습니다
+	MaxBulkLen           int64 // 압축을 푼 문자열의 최대 크기 (0이면 압축 본문 크기로만 제한합니다)
+}
+
+// DefaultLimits는 Redis 기본 설정값입니다
+var DefaultLimits = Limits{ListMaxListpackSize: list.DefaultFill, StreamNodeMaxBytes: 4096, StreamNodeMaxEntries: 100, MaxBulkLen: 512 * 1024 * 1024}
+
+// writeValue는 값의 RDB 타입과 본문을 씁니다 (rdbSaveObjectType, rdbSaveObject)
+func (e *encoder) writeValue(value entity.Entity) bool {
+	switch v := value.(type) {
+	case *entity.StringEntity:
+		e.buf = append(e.buf, TypeString)
+		e.writeString(v.ValueData)
+	case *entity.ListEntity:
+		e.buf = append(e.buf, TypeListQuicklist2)
+		e.writeList(v)
+	case *entity.StreamEntity:
+		e.buf = append(e.buf, TypeStreamListpacks3)
+		e.writeStream(v)
+	default:
+		return false
+	}
+	return true
+}
+
+// writeList는 원소를 list-max-listpack-size 제한에 맞춰 listpack 노드로 묶어 씁니다
+func (e *encoder) writeList(l *entity.ListEntity) {
+	var nodes [][]string
+	var node []string
+	var entry []byte
+	size := lpHeaderSize + 1
+	for _, v := range l.ValueData.LRange(0, -1) {
+		entry = appendLpEntry(entry[:0], string(v))
+		if len(node) > 0 && !list.NodeFits(e.limits.ListMaxListpackSize, len(node)+1, size+len(entry)) {
+			nodes = append(nodes, node)
+			node, size = nil, lpHeaderSize+1
+		}
+		node = append(node, string(v))
+		size += len(entry)
+	}
+	if len(node) > 0 {
+		nodes = append(nodes, node)
+	}
+
+	e.writeLen(uint64(len(nodes)))
+	for _, node := range nodes {
+		e.writeLen(containerPacked)
+		e.writeString(string(buildListpack(node)))
+	}
+}
+
+// writeStream은 엔트리를 stream-node-max-entries, stream-node-max-bytes 제한에 맞춰 노드로 나눠 씁니다 (소비자 그룹은 없습니다)
+// 노드 키는 첫 엔트리(마스터 엔트리) ID이고, 마스터와 필드 이름이 같은 엔트리는 값만 적습니다
+func (e *encoder) writeStream(s *entity.StreamEntity) {
+	entries := make([]entity.StreamEntry, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		if entry.Id != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	nodes := e.streamNodes(entries)
+	e.writeLen(uint64(len(nodes)))
+	for _, node := range nodes {
+		master := node[0]
+		key := make([]byte, 16)
+		binary.BigEndian.PutUint64(key, uint64(master.Id.Millis))
+		binary.BigEndian.PutUint64(key[8:], uint64(master.Id.Seq))
+		e.writeString(string(key))
+		e.writeString(string(buildListpack(streamNodeValues(node))))
+	}
+
+	first := entity.StreamId{}
+	if len(entries) > 0 {
+		first = *entries[0].Id
+	}
+	e.writeLen(uint64(len(entries)))
+	e.writeLen(uint64(s.LastMillis))
+	e.writeLen(uint64(s.LastSeq))
+	e.writeLen(uint64(first.Millis))
+	e.writeLen(uint64(first.Seq))
+	e.writeLen(0) // max_deleted_entry_id (지운 엔트리가 없습니다)
+	e.writeLen(0)
+	e.writeLen(uint64(s.EntriesAdded)) // entries_added
+	e.writeLen(0)                      // 소비자 그룹 수
+}
+
+// streamNodes는 엔트리를 노드로 나눕니다. 노드가 엔트리 수나 크기 제한에 닿으면 새 노드를 엽니다 (Redis streamAppendItem)
+// 크기는 엔트리의 필드와 값 크기에 플래그, ID 차이, lp-count 몫을 더해 어림합니다
+func (e *encoder) streamNodes(entries []entity.StreamEntry) [][]entity.StreamEntry {
+	maxBytes, maxEntries := int(e.limits.StreamNodeMaxBytes), e.limits.StreamNodeMaxEntries
+	var nodes [][]entity.StreamEntry
+	var node []entity.StreamEntry
+	var buf []byte
+	size := 0
+	for _, entry := range entries {
+		buf = buf[:0]
+		for _, f := range entry.Fields {
+			buf = appendLpEntry(appendLpEntry(buf, f.Key), f.Value)
+		}
+		entrySize := len(buf) + 4*2
+		if len(node) > 0 && ((maxEntries > 0 && len(node) >= maxEntries) || (maxBytes > 0 && size+entrySize > maxBytes)) {
+			nodes = append(nodes, node)
+			node, size = nil, 0
+		}
+		node = append(node, entry)
+		size += entrySize
+	}
+	if len(node) > 0 {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// streamNodeValues는 스트림 노드 listpack의 원소입니다
+// 마스터 엔트리(count, deleted, 필드 수, 필드 이름..., 0) 뒤에 엔트리마다 flags, ms-diff, seq-diff, [필드 수, 필드, 값...|값...], lp-count가 옵니다
+func streamNodeValues(node []entity.StreamEntry) []string {
+	master := node[0]
+	values := []string{strconv.Itoa(len(node)), "0", strconv.Itoa(len(master.Fields))}
+	for _, f := range master.Fields {
+		values = append(values, f.Key)
+	}
+	values = append(values, "0")
+
+	for _, entry := range node {
+		sameFields := slices.EqualFunc(entry.Fields, master.Fields, func(a, b entity.FieldValue) bool { return a.Key == b.Key })
+		flags := 0
+		if sameFields {
+			flags = streamItemSameFields
+		}
+		values = append(values, strconv.Itoa(flags),
+			strconv.Itoa(entry.Id.Millis-master.Id.Millis), strconv.Itoa(entry.Id.Seq-master.Id.Seq))
+		if sameFields {
+			for _, f := range entry.Fields {
+				values = append(values, f.Value)
+			}
+			values = append(values, strconv.Itoa(len(entry.Fields)+3))
+		} else {
+			values = append(values, strconv.Itoa(len(entry.Fields)))
+			for _, f := range entry.Fields {
+				values = append(values, f.Key, f.Value)
+			}
+			values = append(values, strconv.Itoa(2*len(entry.Fields)+4))
+		}
+	}
+	return values
+}
+
+// readValue는 typ 타입의 값을 읽습니다 (rdbLoadObject)
+func (d *decoder) readValue(typ byte) (entity.Entity, error) {
+	switch typ {
+	case TypeString:
+		s, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		return &entity.StringEntity{ValueData: s}, nil
+	case TypeList:
+		return d.readLinkedList()
+	case TypeListQuicklist2:
+		return d.readQuicklist()
+	case TypeStreamListpacks, TypeStreamListpacks2, TypeStreamListpacks3:
+		return d.readStream(typ)
+	}
+	return nil, ErrBadFormat
+}
+
+// readLinkedList는 원소를 하나씩 적은 예전 리스트 형식을 읽습니다 (RDB_TYPE_LIST)
+func (d *decoder) readLinkedList() (entity.Entity, error) {
+	n, err := d.readLen()
+	if err != nil {
+		return nil, err
+	}
+	l := entity.NewListEntity(d.limits.ListMaxListpackSize)
+	for ; n > 0; n-- {
+		s, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		l.ValueData.RPush([][]byte{[]byte(s)})
+	}
+	return l, nil
+}
+
+func (d *decoder) readQuicklist() (entity.Entity, error) {
+	nodes, err := d.readLen()
+	if err != nil {
+		return nil, err
+	}
+	l := entity.NewListEntity(d.limits.ListMaxListpackSize)
+	for ; nodes > 0; nodes-- {
+		container, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		blob, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		switch container {
+		case containerPlain:
+			l.ValueData.RPush([][]byte{[]byte(blob)})
+		case containerPacked:
+			values, err := parseListpack([]byte(blob))
+			if err != nil || len(values) == 0 {
+				return nil, ErrBadFormat
+			}
+			for _, v := range values {
+				l.ValueData.RPush([][]byte{[]byte(v)})
+			}
+		default:
+			return nil, ErrBadFormat
+		}
+	}
+	if l.ValueData.Len() == 0 {
+		return nil, ErrBadFormat
+	}
+	return l, nil
+}
+
+// readStream은 스트림 노드와 메타데이터를 읽습니다. 소비자 그룹은 지원하지 않으므로 그룹이 있으면 거부합니다
+func (d *decoder) readStream(typ byte) (entity.Entity, error) {
+	nodes, err := d.readLen()
+	if err != nil {
+		return nil, err
+	}
+	s := entity.NewStreamEntity()
+	for ; nodes > 0; nodes-- {
+		key, err := d.readString()
+		if err != nil || len(key) != 16 {
+			return nil, ErrBadFormat
+		}
+		master := entity.StreamId{
+			Millis: int(binary.BigEndian.Uint64([]byte(key))),
+			Seq:    int(binary.BigEndian.Uint64([]byte(key[8:]))),
+		}
+		lp, err := d.readString()
+		if err != nil {
+			return nil, err
+		}
+		values, err := parseListpack([]byte(lp))
+		if err != nil {
+			return nil, ErrBadFormat
+		}
+		entries, err := parseStreamNode(master, values)
+		if err != nil {
+			return nil, err
+		}
+		s.Entries = append(s.Entries, entries...)
+	}
+
+	// length, last_id
+	meta := []int{0, 0, 0}
+	if typ >= TypeStreamListpacks2 {
+		// first_id, max_deleted_entry_id, entries_added
+		meta = append(meta, 0, 0, 0, 0, 0)
+	}
+	for i := range meta {
+		if meta[i], err = d.readUint(); err != nil {
+			return nil, err
+		}
+	}
+	s.LastMillis, s.LastSeq = meta[1], meta[2]
+	if meta[0] != len(s.Entries) {
+		return nil, ErrBadFormat
+	}
+	// XADD가 지키는 순서를 믿을 수 없는 페이로드에서도 지키도록, ID는 늘어나기만 하고 last_id를 넘지 않아야 합니다
+	prev := &entity.StreamId{}
+	for i, entry := range s.Entries {
+		if i > 0 && !prev.Less(entry.Id) {
+			return nil, ErrBadFormat
+		}
+		prev = entry.Id
+	}
+	if len(s.Entries) > 0 && !prev.Under(&entity.StreamId{Millis: s.LastMillis, Seq: s.LastSeq}) {
+		return nil, ErrBadFormat
+	}
+	s.EntriesAdded = len(s.Entries)
+	if typ >= TypeStreamListpacks2 {
+		s.EntriesAdded = meta[7]
+	}
+	groups, err := d.readLen()
+	if err != nil || groups != 0 {
+		return nil, ErrBadFormat
+	}
+	return s, nil
+}
+
+// parseStreamNode는 노드 listpack 원소를 엔트리로 바꿉니다. 지운 표시가 있는 엔트리는 건너뜁니다
+func parseStreamNode(master entity.StreamId, values []string) ([]entity.StreamEntry, error) {
+	pos := 0
+	next := func() (string, bool) {
+		if pos >= len(values) {
+			return "", false
+		}
+		pos++
+		return values[pos-1], true
+	}
+	nextInt := func() (int, bool) {
+		s, ok := next()
+		if !ok {
+			return 0, false
+		}
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	}
+
+	count, ok1 := nextInt()
+	_, ok2 := nextInt() // deleted
+	numFields, ok3 := nextInt()
+	if !ok1 || !ok2 || !ok3 || numFields < 0 {
+		return nil, ErrBadFormat
+	}
+	fields := make([]string, numFields)
+	for i := range fields {
+		var ok bool
+		if fields[i], ok = next(); !ok {
+			return nil, ErrBadFormat
+		}
+	}
+	if terminator, ok := next(); !ok || terminator != "0" {
+		return nil, ErrBadFormat
+	}
+
+	var entries []entity.StreamEntry
+	for pos < len(values) {
+		flags, ok1 := nextInt()
+		msDiff, ok2 := nextInt()
+		seqDiff, ok3 := nextInt()
+		if !ok1 || !ok2 || !ok3 {
+			return nil, ErrBadFormat
+		}
+		entry := entity.StreamEntry{Id: &entity.StreamId{Millis: master.Millis + msDiff, Seq: master.Seq + seqDiff}}
+		if flags&streamItemSameFields != 0 {
+			for _, field := range fields {
+				value, ok := next()
+				if !ok {
+					return nil, ErrBadFormat
+				}
+				entry.Fields = append(entry.Fields, entity.FieldValue{Key: field, Value: value})
+			}
+		} else {
+			n, ok := nextInt()
+			if !ok || n < 0 {
+				return nil, ErrBadFormat
+			}
+			for i := 0; i < n; i++ {
+				field, ok1 := next()
+				value, ok2 := next()
+				if !ok1 || !ok2 {
+					return nil, ErrBadFormat
+				}
+				entry.Fields = append(entry.Fields, entity.FieldValue{Key: field, Value: value})
+			}
+		}
+		if _, ok := nextInt(); !ok { // lp-count
+			return nil, ErrBadFormat
+		}
+		if flags&streamItemDeleted == 0 {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) != count {
+		return nil, ErrBadFormat
+	}
+	return entries, nil
+}
@@ -1,14 +1,21 @@
 package server
 
 import (
-	"bufio"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/client"
 	"github.com/codecrafters-io/redis-starter-go/app/commands"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/protocol"
 	"github.com/codecrafters-io/redis-starter-go/app/store"
 	"github.com/codecrafters-io/redis-starter-go/app/transaction"
@@ -16,64 +23,210 @@ import (
 )
 
 type Server struct {
-	listener      net.Listener
+	listeners     []net.Listener
 	eventChan     chan types.CommandEvent
 	commandManger *commands.CommandManger
 	shutdownCh    chan struct{}
 	wg            sync.WaitGroup
 	client        *client.Client
 	info          *types.ServerInfo
+	config        *config.Config
 }
 
-func NewServer(addr string, replicaOf string, port int) (*Server, error) {
-	listener, err := net.Listen("tcp", addr)
+func NewServer(cfg *config.Config) (*Server, error) {
+	listeners, err := listen(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to bind to %s: %v", addr, err)
+		return nil, err
 	}
 
-	newStore := store.NewStore()
-	serverInfo := types.NewServerInfo(port, replicaOf)
+	dbs := store.NewDatabases(cfg.Databases)
+	serverInfo := types.NewServerInfo(cfg.Port, cfg.ReplicaOf)
 	fmt.Println("New server info:", serverInfo)
 	var newClient *client.Client
 
 	if serverInfo.IsSlave() {
-		newClient, err = client.NewClient(serverInfo)
+		newClient, err = client.NewClient(serverInfo, cfg)
 		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+	}
+
+	commandManger := commands.NewCommandManger(dbs, serverInfo, cfg)
+	if cfg.ACLFile != "" {
+		if err := commandManger.ACL().LoadFile(cfg.ACLFile); err != nil {
+			closeListeners(listeners)
+			if newClient != nil {
+				newClient.CloseClient()
+			}
 			return nil, err
 		}
 	}
 
 	server := &Server{
-		listener:      listener,
+		listeners:     listeners,
 		eventChan:     make(chan types.CommandEvent, 100), // 버퍼링된 채널
-		commandManger: commands.NewCommandManger(newStore, serverInfo),
+		commandManger: commandManger,
 		shutdownCh:    make(chan struct{}),
 		client:        newClient,
 		info:          serverInfo,
+		config:        cfg,
 	}
 
 	return server, nil
 }
 
+// listen은 bind 주소마다 일반 TCP 포트와 (설정된 경우) TLS 포트 리스너를 열고, 유닉스 소켓 리스너를 엽니다
+// port가 0이면 일반 TCP 포트는 열지 않습니다
+func listen(cfg *config.Config) ([]net.Listener, error) {
+	var listeners []net.Listener
+
+	if cfg.Port != 0 {
+		tcpListeners, err := listenTCP(cfg.Bind, cfg.Port)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, tcpListeners...)
+	}
+
+	if cfg.TLSEnabled() {
+		tlsConfig, err := cfg.ServerTLSConfig()
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+
+		tcpListeners, err := listenTCP(cfg.Bind, cfg.TLSPort)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		for _, listener := range tcpListeners {
+			listeners = append(listeners, tls.NewListener(listener, tlsConfig))
+		}
+	}
+
+	if cfg.UnixSocket != "" {
+		listener, err := listenUnix(cfg.UnixSocket, cfg.UnixSocketPerm)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("configured to not listen anywhere")
+	}
+	return listeners, nil
+}
+
+// listenTCP는 bind 주소마다 port로 리스너를 엽니다
+// "*"는 모든 IPv4 주소, "::*"는 모든 IPv6 주소이고, '-'로 시작하는 주소는 시스템에 없으면 건너뜁니다
+func listenTCP(bind []string, port int) ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, bindAddr := range bind {
+		host, optional := strings.CutPrefix(bindAddr, "-")
+
+		network := "tcp"
+		switch host {
+		case "*":
+			network, host = "tcp4", "0.0.0.0"
+		case "::*":
+			network, host = "tcp6", "::"
+		default:
+			// IPv4와 IPv6를 따로 열어야 "*"와 "::*"를 함께 bind해도 주소가 겹치지 않습니다
+			if ip := net.ParseIP(host); ip != nil {
+				network = "tcp6"
+				if ip.To4() != nil {
+					network = "tcp4"
+				}
+			}
+		}
+
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		listener, err := net.Listen(network, addr)
+		if err != nil {
+			if optional && isAddrUnavailable(err) {
+				fmt.Printf("Skipping optional bind address %s: %v\n", addr, err)
+				continue
+			}
+			closeListeners(listeners)
+			return nil, fmt.Errorf("failed to bind to %s: %v", addr, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// isAddrUnavailable은 주소나 주소 체계(IPv6 등)를 이 시스템에서 쓸 수 없어 bind에 실패했는지 확인합니다
+func isAddrUnavailable(err error) bool {
+	return errors.Is(err, syscall.EADDRNOTAVAIL) ||
+		errors.Is(err, syscall.EAFNOSUPPORT) ||
+		errors.Is(err, syscall.EPROTONOSUPPORT)
+}
+
+// listenUnix는 유닉스 소켓 리스너를 엽니다
+// 이전 실행에서 남은 소켓 파일은 지우고, perm이 있으면 소켓 파일 권한을 바꿉니다
+func listenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale unix socket %s: %v", path, err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to unix socket %s: %v", path, err)
+	}
+
+	if perm != 0 {
+		if err := os.Chmod(path, perm); err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("failed to set unix socket permissions: %v", err)
+		}
+	}
+	return listener, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+}
+
 func (s *Server) Start() {
 	if s.info.IsSlave() {
-		s.SlaveStart()
-		return
+		if err := s.SlaveStart(); err != nil {
+			fmt.Println(err)
+			return
+		}
 	}
 
-	fmt.Printf("Redis server starting on %s\n", s.listener.Addr().String())
+	for _, listener := range s.listeners {
+		fmt.Printf("Redis server starting on %s\n", listener.Addr().String())
+	}
 
 	// 이벤트 루프를 별도 고루틴에서 시작
 	s.wg.Add(1)
 	go s.eventLoop()
 
-	// 클라이언트 연결을 받는 메인 루프
+	s.wg.Add(1)
+	go s.cron()
+
+	// 리스너마다 클라이언트 연결을 받는 루프를 돌립니다
+	for _, listener := range s.listeners[1:] {
+		go s.acceptLoop(listener)
+	}
+	s.acceptLoop(s.listeners[0])
+}
+
+// acceptLoop는 리스너에서 클라이언트 연결을 받아 처리 고루틴을 띄웁니다
+func (s *Server) acceptLoop(listener net.Listener) {
 	for {
 		select {
 		case <-s.shutdownCh:
 			return
 		default:
-			conn, err := s.listener.Accept()
+			conn, err := listener.Accept()
 			if err != nil {
 				select {
 				case <-s.shutdownCh:
@@ -84,10 +237,81 @@ func (s *Server) Start() {
 				}
 			}
 
-			fmt.Printf("New connection: %s\n", conn.RemoteAddr())
+			fmt.Printf("New connection: %s\n", types.ConnAddr(conn))
+			s.setKeepAlive(conn)
+
+			// 연결 목록에 바로 넣어야 연결이 몰려도 maxclients를 넘지 않습니다
+			if s.maxClientsReached() {
+				s.wg.Add(1)
+				go s.reject(conn, "ERR max number of clients reached")
+				continue
+			}
+			ctx := s.newConnContext(conn)
 
 			s.wg.Add(1)
-			go s.handleConnection(conn)
+			go s.handleConnection(ctx)
+		}
+	}
+}
+
+// setKeepAlive는 tcp-keepalive 설정을 새 TCP 연결에 적용합니다 (0이면 keepalive를 끕니다)
+func (s *Server) setKeepAlive(conn net.Conn) {
+	s.config.RLock()
+	period := time.Duration(s.config.TCPKeepAlive) * time.Second
+	s.config.RUnlock()
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return
+	}
+	if period <= 0 {
+		_ = tcpConn.SetKeepAlive(false)
+		return
+	}
+	_ = tcpConn.SetKeepAlive(true)
+	_ = tcpConn.SetKeepAlivePeriod(period)
+}
+
+// cronInterval은 유휴 연결 정리 같은 주기 작업의 간격입니다
+const cronInterval = 100 * time.Millisecond
+
+// cron은 서버가 멈출 때까지 주기 작업을 돌립니다
+func (s *Server) cron() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(cronInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.closeIdleClients()
+		case <-s.shutdownCh:
+			return
+		}
+	}
+}
+
+// closeIdleClients는 timeout 초 넘게 명령어를 보내지 않은 일반 클라이언트를 닫습니다
+// 블로킹 명령어로 기다리는 클라이언트와 복제 연결, pub/sub 클라이언트는 닫지 않습니다
+func (s *Server) closeIdleClients() {
+	s.config.RLock()
+	timeout := time.Duration(s.config.Timeout) * time.Second
+	s.config.RUnlock()
+	if timeout <= 0 {
+		return
+	}
+
+	for _, ctx := range s.commandManger.Clients().List() {
+		if ctx.Type() != "normal" || ctx.Blocked() || ctx.IsClosed() {
+			continue
+		}
+		if ctx.Idle() > timeout {
+			fmt.Printf("Closing idle client: %s\n", ctx.Addr())
+			ctx.Close()
+			s.info.GetStats().TimeoutDisconnections.Add(1)
 		}
 	}
 }
@@ -95,11 +319,12 @@ func (s *Server) Start() {
 func (s *Server) Stop() {
 	fmt.Println("Server shutting down...")
 
-	s.client.CloseClient()
+	if s.client != nil {
+		s.client.CloseClient()
+	}
 
 	close(s.shutdownCh)
-	s.listener.Close()
-	close(s.eventChan)
+	closeListeners(s.listeners)
 
 	s.wg.Wait()
 	fmt.Println("Server stopped")
@@ -108,9 +333,88 @@ func (s *Server) Stop() {
 func (s *Server) eventLoop() {
 	defer s.wg.Done()
 
-	for event := range s.eventChan {
-		s.processEvent(event)
-		s.info.AddOffset(len(event.Raw))
+	// CLIENT PAUSE 동안 미뤄 둔 명령어 (연결 고루틴은 Done을 기다리며 멈춰 있습니다)
+	var held []types.CommandEvent
+
+	// 능동 만료는 복제 스트림 순서를 지키도록 명령어와 같은 이벤트 루프에서 hz마다 실행합니다
+	period := s.hzPeriod()
+	expireTicker := time.NewTicker(period)
+	defer expireTicker.Stop()
+
+	for {
+		held = s.releaseHeld(held)
+
+		var unpause <-chan time.Time
+		if len(held) > 0 {
+			unpause = time.After(s.commandManger.PauseRemaining())
+		}
+
+		var event types.CommandEvent
+		select {
+		case event = <-s.eventChan:
+		case <-unpause:
+			continue
+		case <-expireTicker.C:
+			// 레플리카는 능동 만료를 하지 않고 마스터가 보내는 DEL을 기다립니다
+			if !s.info.IsSlave() {
+				s.commandManger.ActiveExpire()
+			}
+			if p := s.hzPeriod(); p != period {
+				period = p
+				expireTicker.Reset(period)
+			}
+			continue
+		case <-s.shutdownCh:
+			return
+		}
+
+		if s.commandManger.IsPaused(event) {
+			// 파이프라인 앞쪽에서 이미 실행한 명령어의 응답은 pause가 풀릴 때까지 붙잡아 두지 않고 내보냅니다
+			_ = event.Ctx.Flush()
+			s.commandManger.FlushReplicas()
+			event.Ctx.SetBlocked(true)
+			held = append(held, event)
+			continue
+		}
+		s.runEvent(event)
+	}
+}
+
+// hzPeriod는 hz 설정에 따른 주기 작업 간격입니다
+func (s *Server) hzPeriod() time.Duration {
+	s.config.RLock()
+	defer s.config.RUnlock()
+	return time.Second / time.Duration(s.config.Hz)
+}
+
+// releaseHeld는 미뤄 둔 명령어 중 이제 실행할 수 있는 것을 도착한 순서대로 실행하고 나머지를 반환합니다
+func (s *Server) releaseHeld(held []types.CommandEvent) []types.CommandEvent {
+	remaining := held[:0]
+	for _, event := range held {
+		if s.commandManger.IsPaused(event) {
+			remaining = append(remaining, event)
+			continue
+		}
+		event.Ctx.SetBlocked(false)
+		s.runEvent(event)
+	}
+	return remaining
+}
+
+func (s *Server) runEvent(event types.CommandEvent) {
+	s.processEvent(event)
+	s.commandManger.PropagateLazyExpired()
+	event.Ctx.FinishCommand()
+	s.info.AddOffset(event.RawLen)
+
+	// 파이프라인 묶음의 마지막 명령어까지 처리했으면 쌓인 응답을 한 번에 내보냅니다
+	if !event.Pipelined {
+		_ = event.Ctx.Flush()
+		s.commandManger.FlushReplicas()
+	}
+
+	if event.Done != nil {
+		event.Done <- struct{}{}
 	}
 }
 
@@ -122,10 +426,52 @@ func (s *Server) processEvent(event types.CommandEvent) {
 		return
 	}
 
+	// 인증 전에는 AUTH만 받습니다
+	spec := s.commandManger.GetSpec(event.Command, event.Args)
+	event.Ctx.SetLastCommand(spec.Name)
+	if !spec.NoAuth {
+		if !event.Ctx.IsAuthenticated() {
+			event.Ctx.Write(protocol.AppendError(nil, "NOAUTH Authentication required."))
+			return
+		}
+		if !s.checkPermission(event, spec) {
+			return
+		}
+	}
+
+	// 구독 중인 연결은 구독 관련 명령어와 PING만 보낼 수 있습니다
+	if !s.commandManger.CheckSubscribed(event) {
+		return
+	}
+
+	// maxmemory를 넘었으면 키를 쫓아내고, 그래도 넘치면 메모리를 늘리는 명령어를 거부합니다
+	// 레플리카는 마스터가 보내는 DEL을 따르므로 스스로 쫓아내지 않습니다 (replica-ignore-maxmemory)
+	if !s.info.IsSlave() && !s.commandManger.CheckMemory(event) {
+		return
+	}
+
 	wrappedHandler := s.wrapHandlerForTransaction(*handler, event.Command)
 	wrappedHandler(event)
 }
 
+// checkPermission은 명령어를 실행하기 전에 사용자의 ACL 권한을 확인합니다
+// 거부되면 ACL LOG에 남기고 NOPERM 에러로 응답합니다
+func (s *Server) checkPermission(event types.CommandEvent, spec commands.CommandSpec) bool {
+	acl := s.commandManger.ACL()
+	denied := acl.Check(event.Ctx.User(), spec.Name, spec.Keys(event.Args))
+	if denied == nil {
+		return true
+	}
+
+	context := "toplevel"
+	if event.Ctx.GetTransaction().IsInTransaction() {
+		context = "multi"
+	}
+	acl.AddLogEntry(denied.Reason, context, denied.Object, event.Ctx.Username(), event.Ctx.ClientInfo())
+	event.Ctx.Write(protocol.AppendError(nil, denied.Error()))
+	return false
+}
+
 func (s *Server) wrapHandlerForTransaction(handler types.Handler, commandName string) types.Handler {
 	return func(e types.CommandEvent) {
 		if transaction.IsTransactionCommand(commandName) {
@@ -144,59 +490,97 @@ func (s *Server) wrapHandlerForTransaction(handler types.Handler, commandName st
 	}
 }
 
-func (s *Server) handleReplicaConnection(conn net.Conn) {
+// handleReplicaConnection은 레플리카 쪽에서 마스터 연결로 들어오는 복제 스트림을 처리합니다
+func (s *Server) handleReplicaConnection(conn net.Conn, reader io.Reader) {
 	defer s.wg.Done()
-	defer conn.Close()
 
-	ctx := types.NewConnContext(conn, transaction.NewTransaction())
-	reader := bufio.NewReader(conn)
+	ctx := s.newConnContext(conn)
+	ctx.SetMaster()
+	defer s.commandManger.Clients().Remove(ctx)
+	defer ctx.CloseAfterReply()
+	s.readCommands(ctx, reader)
+}
 
-	for {
-		select {
-		case <-s.shutdownCh:
-			return
-		default:
-		}
+func (s *Server) handleConnection(ctx *types.ConnContext) {
+	defer s.wg.Done()
+	defer s.commandManger.Clients().Remove(ctx)
+	defer s.commandManger.UnsubscribeAll(ctx)
+	defer ctx.CloseAfterReply()
+
+	if s.protectedModeDenied(ctx.Conn) {
+		fmt.Printf("Refusing connection from %s in protected mode\n", ctx.Addr())
+		ctx.Write(protocol.AppendError(nil, protectedModeError))
+		s.info.GetStats().RejectedConnections.Add(1)
+		return
+	}
+	s.readCommands(ctx, ctx.Conn)
+}
 
-		resp, err := protocol.ReadRESP(reader)
-		if err != nil {
-			if err.Error() == "EOF" {
-				fmt.Printf("Client disconnected: %s\n", conn.RemoteAddr())
-				return
-			}
-			fmt.Printf("Error reading from %s: %v\n", conn.RemoteAddr(), err)
-			return
-		}
+// rejectTimeout은 거절 메시지를 보낼 때 기다리는 최대 시간입니다 (TLS 핸드셰이크 포함)
+const rejectTimeout = time.Second
 
-		if resp.Type == protocol.Array && resp.Length > 0 {
-			cmd := strings.ToUpper(string(resp.Arr[0].Data))
-			args := make([][]byte, 0, resp.Length-1)
+// reject는 받을 수 없는 연결에 에러를 보내고 바로 닫습니다
+func (s *Server) reject(conn net.Conn, message string) {
+	defer s.wg.Done()
 
-			for _, arg := range resp.Arr[1:] {
-				args = append(args, arg.Data)
-			}
+	fmt.Printf("Rejecting connection from %s: %s\n", types.ConnAddr(conn), message)
+	s.info.GetStats().RejectedConnections.Add(1)
+	_ = conn.SetDeadline(time.Now().Add(rejectTimeout))
+	_, _ = conn.Write(protocol.AppendError(nil, message))
+	_ = conn.Close()
+}
 
-			fmt.Print(cmd)
-			for _, arg := range args {
-				fmt.Printf(" %s", string(arg))
-			}
-			fmt.Println()
+// maxClientsReached는 연결된 클라이언트 수가 maxclients에 이르렀는지 확인합니다
+func (s *Server) maxClientsReached() bool {
+	s.config.RLock()
+	maxClients := s.config.MaxClients
+	s.config.RUnlock()
+	return s.commandManger.Clients().Len() >= maxClients
+}
 
-			select {
-			case s.eventChan <- types.CommandEvent{Command: cmd, Args: args, Ctx: ctx, Raw: resp.Raw}:
-			case <-s.shutdownCh:
-				return
-			}
-		}
+// protectedModeError는 Redis가 protected mode에서 외부 연결을 거절할 때 보내는 안내문입니다
+const protectedModeError = "DENIED Redis is running in protected mode because protected mode is enabled and no password is set for the default user. " +
+	"In this mode connections are only accepted from the loopback interface. " +
+	"If you want to connect from external computers to Redis you may adopt one of the following solutions: " +
+	"1) Just disable protected mode sending the command 'CONFIG SET protected-mode no' from the loopback interface by connecting to Redis from the same host the server is running, however MAKE SURE Redis is not publicly accessible from internet if you do so. Use CONFIG REWRITE to make this change permanent. " +
+	"2) Alternatively you can just disable the protected mode by editing the Redis configuration file, and setting the protected mode option to 'no', and then restarting the server. " +
+	"3) If you started the server manually just for testing, restart it with the '--protected-mode no' option. " +
+	"4) Set up an authentication password for the default user. " +
+	"NOTE: You only need to do one of the above things in order for the server to start accepting connections from the outside."
+
+// protectedModeDenied는 protected mode이고 default 사용자에 비밀번호가 없을 때 루프백이 아닌 연결인지 확인합니다
+func (s *Server) protectedModeDenied(conn net.Conn) bool {
+	s.config.RLock()
+	protected := s.config.ProtectedMode
+	s.config.RUnlock()
+
+	if !protected || !s.commandManger.ACL().DefaultUserNoPass() {
+		return false
 	}
+	return !isLocalConn(conn)
 }
 
-func (s *Server) handleConnection(conn net.Conn) {
-	defer s.wg.Done()
-	defer conn.Close()
+// isLocalConn은 루프백 주소나 유닉스 소켓으로 들어온 연결인지 확인합니다
+func isLocalConn(conn net.Conn) bool {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP.IsLoopback()
+	}
+	return true
+}
+
+func (s *Server) newConnContext(conn net.Conn) *types.ConnContext {
+	ctx := types.NewConnContext(conn, transaction.NewTransaction(), s.config, s.info.GetStats())
+	// default 사용자가 nopass면 AUTH 없이 인증된 상태로 시작합니다
+	ctx.SetUser(s.commandManger.ACL().DefaultUser())
+	s.commandManger.Clients().Add(ctx)
+	return ctx
+}
 
-	ctx := types.NewConnContext(conn, transaction.NewTransaction())
-	reader := bufio.NewReader(conn)
+// readCommands는 연결에서 명령어를 읽어 이벤트 루프로 넘깁니다
+// 인수는 리더의 버퍼를 그대로 가리키므로, 이벤트 루프가 처리를 마칠 때까지 기다린 뒤 다음 명령어를 읽습니다
+func (s *Server) readCommands(ctx *types.ConnContext, rd io.Reader) {
+	reader := protocol.NewReader(rd)
+	done := make(chan struct{}, 1)
 
 	for {
 		select {
@@ -205,73 +589,62 @@ func (s *Server) handleConnection(conn net.Conn) {
 		default:
 		}
 
-		resp, err := protocol.ReadRESP(reader)
+		// CONFIG SET으로 바뀐 제한이 이미 연결된 클라이언트에도 적용되도록 명령어마다 다시 읽습니다
+		reader.SetLimits(s.readerLimits())
+		args, rawLen, err := reader.ReadCommand()
 		if err != nil {
-			if err.Error() == "EOF" {
-				fmt.Printf("Client disconnected: %s\n", conn.RemoteAddr())
+			var protoErr *protocol.ProtocolError
+			if errors.As(err, &protoErr) {
+				// Redis와 같이 에러를 알린 뒤 연결을 닫습니다
+				ctx.Write(protocol.AppendError([]byte{}, "ERR "+protoErr.Error()))
+				_ = ctx.Flush()
+				fmt.Printf("Protocol error from %s: %v\n", ctx.Addr(), err)
 				return
 			}
-			fmt.Printf("Error reading from %s: %v\n", conn.RemoteAddr(), err)
+			if err == io.EOF {
+				fmt.Printf("Client disconnected: %s\n", ctx.Addr())
+				return
+			}
+			fmt.Printf("Error reading from %s: %v\n", ctx.Addr(), err)
 			return
 		}
 
-		if resp.Type == protocol.Array && resp.Length > 0 {
-			cmd := strings.ToUpper(string(resp.Arr[0].Data))
-			args := make([][]byte, 0, resp.Length-1)
+		ctx.Touch(reader.Buffered())
 
-			for _, arg := range resp.Arr[1:] {
-				args = append(args, arg.Data)
-			}
+		cmd := strings.ToUpper(string(args[0]))
+		args = args[1:]
 
-			fmt.Print(cmd)
-			for _, arg := range args {
-				fmt.Printf(" %s", string(arg))
-			}
-			fmt.Println()
+		select {
+		case s.eventChan <- types.CommandEvent{Command: cmd, Args: args, Ctx: ctx, RawLen: rawLen, Done: done, Pipelined: reader.HasCommand()}:
+		case <-s.shutdownCh:
+			return
+		}
 
-			select {
-			case s.eventChan <- types.CommandEvent{Command: cmd, Args: args, Ctx: ctx, Raw: resp.Raw}:
-			case <-s.shutdownCh:
-				return
-			}
+		select {
+		case <-done:
+		case <-s.shutdownCh:
+			return
 		}
 	}
 }
 
-func (s *Server) SlaveStart() {
-	fmt.Printf("Redis slave starting on %s\n", s.listener.Addr().String())
-	// 이벤트 루프를 별도 고루틴에서 시작
+func (s *Server) readerLimits() protocol.Limits {
+	s.config.RLock()
+	defer s.config.RUnlock()
+	return protocol.Limits{
+		MaxBulkLen:     s.config.ProtoMaxBulkLen,
+		MaxQueryBuffer: s.config.ClientQueryBufferLimit,
+	}
+}
 
-	err := s.client.Init()
-	fmt.Println(err)
-	if err != nil {
-		return
+// SlaveStart는 마스터와 핸드셰이크를 마치고 복제 스트림을 읽기 시작합니다
+func (s *Server) SlaveStart() error {
+	if err := s.client.Init(); err != nil {
+		return err
 	}
 	s.info.InitOffset()
 
 	s.wg.Add(1)
-	go s.handleReplicaConnection(s.client.GetConn())
-	go s.eventLoop()
-
-	// 클라이언트 연결을 받는 메인 루프
-	for {
-		select {
-		case <-s.shutdownCh:
-			return
-		default:
-			conn, err := s.listener.Accept()
-			if err != nil {
-				select {
-				case <-s.shutdownCh:
-					return
-				default:
-					fmt.Printf("Error accepting connection: %v\n", err)
-					continue
-				}
-			}
-			fmt.Printf("New connection: %s\n", conn.RemoteAddr())
-			s.wg.Add(1)
-			go s.handleConnection(conn)
-		}
-	}
+	go s.handleReplicaConnection(s.client.GetConn(), s.client.GetReader())
+	return nil
 }
@@ -0,0 +1,1521 @@
+package server
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// testCerts는 테스트용 자체 서명 CA와 그 CA로 서명한 인증서 파일 경로입니다
+type testCerts struct {
+	certFile, keyFile, caFile string
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("PEM 파일 쓰기 실패: %v", err)
+	}
+}
+
+func generateCerts(t *testing.T) testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certs := testCerts{
+		certFile: filepath.Join(dir, "redis.crt"),
+		keyFile:  filepath.Join(dir, "redis.key"),
+		caFile:   filepath.Join(dir, "ca.crt"),
+	}
+	writePEM(t, certs.caFile, "CERTIFICATE", caDER)
+	writePEM(t, certs.certFile, "CERTIFICATE", der)
+	writePEM(t, certs.keyFile, "EC PRIVATE KEY", keyDER)
+	return certs
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func tlsTestConfig(certs testCerts) *config.Config {
+	cfg := config.Default()
+	cfg.Port = 0
+	cfg.TLSCertFile = certs.certFile
+	cfg.TLSKeyFile = certs.keyFile
+	cfg.TLSCACertFile = certs.caFile
+	return cfg
+}
+
+func startTestServer(t *testing.T, cfg *config.Config) *Server {
+	t.Helper()
+	// bind를 따로 정하지 않은 테스트는 루프백에서만 받습니다
+	if slices.Equal(cfg.Bind, config.Default().Bind) {
+		cfg.Bind = []string{"127.0.0.1"}
+	}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("서버 생성 실패: %v", err)
+	}
+	go s.Start()
+	// 연결은 서버보다 늦게 열리므로 t.Cleanup의 역순 실행 덕분에 서버를 멈추기 전에 먼저 닫힙니다
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// roundTrip은 명령어 하나를 보내고 응답 한 줄을 읽습니다
+func roundTrip(t *testing.T, conn net.Conn, reader *bufio.Reader, msg string) string {
+	t.Helper()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		t.Fatalf("쓰기 실패: %v", err)
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("읽기 실패: %v", err)
+	}
+	return line
+}
+
+func TestTLSClient(t *testing.T) {
+	certs := generateCerts(t)
+	cfg := tlsTestConfig(certs)
+	cfg.TLSPort = freePort(t)
+	startTestServer(t, cfg)
+
+	clientConfig, err := cfg.ClientTLSConfig("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "127.0.0.1:" + strconv.Itoa(cfg.TLSPort)
+
+	conn, err := tls.Dial("tcp", addr, clientConfig)
+	if err != nil {
+		t.Fatalf("TLS 접속 실패: %v", err)
+	}
+	if got := roundTrip(t, conn, bufio.NewReader(conn), "*1\r\n$4\r\nPING\r\n"); got != "+PONG\r\n" {
+		t.Errorf("PING 응답이 다름. got=%q", got)
+	}
+	conn.Close()
+
+	// tls-auth-clients yes에서는 클라이언트 인증서 없이 명령어를 보낼 수 없습니다
+	noCert := clientConfig.Clone()
+	noCert.Certificates = nil
+	conn, err = tls.Dial("tcp", addr, noCert)
+	if err == nil {
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, _ = conn.Write([]byte("*1\r\n$4\r\nPING\r\n"))
+		_, err = bufio.NewReader(conn).ReadString('\n')
+		conn.Close()
+	}
+	if err == nil {
+		t.Errorf("클라이언트 인증서 없는 접속은 거부되어야 함")
+	}
+}
+
+func TestTLSReplication(t *testing.T) {
+	certs := generateCerts(t)
+
+	masterConfig := tlsTestConfig(certs)
+	masterConfig.TLSPort = freePort(t)
+	startTestServer(t, masterConfig)
+
+	replicaConfig := tlsTestConfig(certs)
+	replicaConfig.Port = freePort(t)
+	replicaConfig.ReplicaOf = "127.0.0.1 " + strconv.Itoa(masterConfig.TLSPort)
+	replicaConfig.TLSReplication = true
+	startTestServer(t, replicaConfig)
+
+	clientConfig, err := masterConfig.ClientTLSConfig("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterConn, err := tls.Dial("tcp", "127.0.0.1:"+strconv.Itoa(masterConfig.TLSPort), clientConfig)
+	if err != nil {
+		t.Fatalf("마스터 접속 실패: %v", err)
+	}
+	defer masterConn.Close()
+	masterReader := bufio.NewReader(masterConn)
+
+	replicaConn, replicaReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(replicaConfig.Port))
+
+	assertReplicated(t, masterConn, masterReader, replicaConn, replicaReader)
+}
+
+// assertReplicated는 마스터에 쓴 값이 레플리카에서 보이는지 확인합니다
+// 핸드셰이크가 끝나기 전의 SET은 복제되지 않으므로 값이 보일 때까지 반복합니다
+func assertReplicated(t *testing.T, masterConn net.Conn, masterReader *bufio.Reader, replicaConn net.Conn, replicaReader *bufio.Reader) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got := roundTrip(t, masterConn, masterReader, "*3\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$3\r\nbar\r\n"); got != "+OK\r\n" {
+			t.Fatalf("SET 응답이 다름. got=%q", got)
+		}
+		got := roundTrip(t, replicaConn, replicaReader, "*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\n")
+		if got == "$3\r\n" {
+			if value, _ := replicaReader.ReadString('\n'); value != "bar\r\n" {
+				t.Errorf("레플리카에 복제된 값이 다름. got=%q", value)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("마스터의 쓰기가 레플리카에 복제되지 않음. got=%q", got)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = 0
+	cfg.UnixSocket = filepath.Join(t.TempDir(), "redis.sock")
+	cfg.UnixSocketPerm = 0700
+	startTestServer(t, cfg)
+
+	info, err := os.Stat(cfg.UnixSocket)
+	if err != nil {
+		t.Fatalf("소켓 파일이 없음: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("소켓 파일 권한이 다름. got=%o, want=700", info.Mode().Perm())
+	}
+
+	conn, err := net.Dial("unix", cfg.UnixSocket)
+	if err != nil {
+		t.Fatalf("유닉스 소켓 접속 실패: %v", err)
+	}
+	defer conn.Close()
+	if got := roundTrip(t, conn, bufio.NewReader(conn), "*1\r\n$4\r\nPING\r\n"); got != "+PONG\r\n" {
+		t.Errorf("PING 응답이 다름. got=%q", got)
+	}
+}
+
+func TestRequirePass(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.RequirePass = "secret"
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	for _, tc := range []struct{ msg, want string }{
+		{"*1\r\n$4\r\nPING\r\n", "-NOAUTH Authentication required.\r\n"},
+		{"*2\r\n$4\r\nAUTH\r\n$5\r\nwrong\r\n", "-WRONGPASS invalid username-password pair or user is disabled.\r\n"},
+		{"*3\r\n$4\r\nAUTH\r\n$5\r\nalice\r\n$6\r\nsecret\r\n", "-WRONGPASS invalid username-password pair or user is disabled.\r\n"},
+		{"*1\r\n$4\r\nPING\r\n", "-NOAUTH Authentication required.\r\n"},
+		{"*3\r\n$4\r\nAUTH\r\n$7\r\ndefault\r\n$6\r\nsecret\r\n", "+OK\r\n"},
+		{"*1\r\n$4\r\nPING\r\n", "+PONG\r\n"},
+	} {
+		if got := roundTrip(t, conn, reader, tc.msg); got != tc.want {
+			t.Errorf("%q 응답이 다름. got=%q, want=%q", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestMasterAuth(t *testing.T) {
+	masterConfig := config.Default()
+	masterConfig.Port = freePort(t)
+	masterConfig.RequirePass = "secret"
+	startTestServer(t, masterConfig)
+
+	replicaConfig := config.Default()
+	replicaConfig.Port = freePort(t)
+	replicaConfig.ReplicaOf = "127.0.0.1 " + strconv.Itoa(masterConfig.Port)
+	replicaConfig.MasterAuth = "secret"
+	startTestServer(t, replicaConfig)
+
+	masterConn, masterReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(masterConfig.Port))
+	if got := roundTrip(t, masterConn, masterReader, "*2\r\n$4\r\nAUTH\r\n$6\r\nsecret\r\n"); got != "+OK\r\n" {
+		t.Fatalf("AUTH 응답이 다름. got=%q", got)
+	}
+
+	replicaConn, replicaReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(replicaConfig.Port))
+	assertReplicated(t, masterConn, masterReader, replicaConn, replicaReader)
+}
+
+func TestACLEnforcement(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	for _, tc := range []struct{ msg, want string }{
+		{"ACL SETUSER alice on >pw ~cache:* +@read\r\n", "+OK\r\n"},
+		{"AUTH alice pw\r\n", "+OK\r\n"},
+		{"GET cache:1\r\n", "$-1\r\n"},
+		{"GET session:1\r\n", "-NOPERM No permissions to access a key\r\n"},
+		{"SET cache:1 v\r\n", "-NOPERM User alice has no permissions to run the 'set' command\r\n"},
+		{"ACL WHOAMI\r\n", "-NOPERM User alice has no permissions to run the 'acl|whoami' command\r\n"},
+		{"AUTH default x\r\n", "+OK\r\n"},
+		{"ACL WHOAMI\r\n", "$7\r\n"},
+	} {
+		if got := roundTrip(t, conn, reader, tc.msg); got != tc.want {
+			t.Errorf("%q 응답이 다름. got=%q, want=%q", tc.msg, got, tc.want)
+		}
+	}
+	if got, _ := reader.ReadString('\n'); got != "default\r\n" {
+		t.Errorf("ACL WHOAMI 결과가 다름. got=%q", got)
+	}
+
+	if got := roundTrip(t, conn, reader, "ACL LOG 1\r\n"); got != "*1\r\n" {
+		t.Errorf("ACL LOG에 거부 기록이 있어야 함. got=%q", got)
+	}
+}
+
+// externalIPv4는 테스트 머신의 루프백이 아닌 IPv4 주소를 찾습니다
+func externalIPv4(t *testing.T) string {
+	t.Helper()
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Skipf("인터페이스 주소를 읽을 수 없음: %v", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	t.Skip("루프백이 아닌 IPv4 주소가 없음")
+	return ""
+}
+
+func TestBindAndProtectedMode(t *testing.T) {
+	externalIP := externalIPv4(t)
+
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.Bind = []string{"127.0.0.1", externalIP}
+	if _, err := NewServer(withBind(cfg, "192.0.2.1")); err == nil {
+		t.Fatalf("열 수 없는 bind 주소는 에러가 나야 함")
+	}
+
+	// '-'가 붙은 주소는 열 수 없어도 건너뜁니다
+	startTestServer(t, withBind(cfg, "-192.0.2.1"))
+
+	localConn, localReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	if got := roundTrip(t, localConn, localReader, "PING\r\n"); got != "+PONG\r\n" {
+		t.Errorf("루프백 연결은 받아야 함. got=%q", got)
+	}
+
+	externalAddr := net.JoinHostPort(externalIP, strconv.Itoa(cfg.Port))
+	externalConn, externalReader := dialServer(t, externalAddr)
+	if got := roundTrip(t, externalConn, externalReader, "PING\r\n"); !strings.HasPrefix(got, "-DENIED Redis is running in protected mode") {
+		t.Errorf("외부 연결은 protected mode로 거절해야 함. got=%q", got)
+	}
+	externalConn.Close()
+
+	if got := roundTrip(t, localConn, localReader, "CONFIG SET protected-mode no\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CONFIG SET 응답이 다름. got=%q", got)
+	}
+	externalConn, externalReader = dialServer(t, externalAddr)
+	if got := roundTrip(t, externalConn, externalReader, "PING\r\n"); got != "+PONG\r\n" {
+		t.Errorf("protected mode를 끄면 외부 연결도 받아야 함. got=%q", got)
+	}
+}
+
+// withBind는 cfg의 bind 목록 뒤에 주소를 더한 설정 사본을 만듭니다
+func withBind(cfg *config.Config, addr string) *config.Config {
+	c := config.Default()
+	c.Port = cfg.Port
+	c.Bind = append(slices.Clone(cfg.Bind), addr)
+	return c
+}
+
+func TestClientCommands(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	other, otherReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	if got := roundTrip(t, other, otherReader, "CLIENT SETNAME worker\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT SETNAME 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, other, otherReader, "CLIENT SETNAME \"bad name\"\r\n"); !strings.HasPrefix(got, "-ERR Client names cannot contain spaces") {
+		t.Errorf("공백이 든 이름은 거부해야 함. got=%q", got)
+	}
+	if got := roundTrip(t, other, otherReader, "CLIENT GETNAME\r\n"); got != "$6\r\n" {
+		t.Errorf("CLIENT GETNAME 응답이 다름. got=%q", got)
+	}
+	_, _ = otherReader.ReadString('\n')
+
+	otherID := strings.TrimSpace(strings.TrimPrefix(roundTrip(t, other, otherReader, "CLIENT ID\r\n"), ":"))
+
+	if got := roundTrip(t, conn, reader, "CLIENT LIST ID "+otherID+"\r\n"); !strings.HasPrefix(got, "$") {
+		t.Fatalf("CLIENT LIST 응답이 다름. got=%q", got)
+	}
+	line, _ := reader.ReadString('\n')
+	for _, want := range []string{"id=" + otherID + " ", "name=worker ", "flags=N ", "cmd=client|id ", "user=default"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("CLIENT LIST에 %q가 있어야 함. got=%q", want, line)
+		}
+	}
+	_, _ = reader.ReadString('\n')
+
+	// CLIENT REPLY OFF, SKIP 동안의 응답은 오지 않고 ON의 OK부터 다시 옵니다
+	_, _ = conn.Write([]byte("CLIENT REPLY SKIP\r\nPING\r\nCLIENT REPLY OFF\r\nPING\r\n"))
+	if got := roundTrip(t, conn, reader, "CLIENT REPLY ON\r\n"); got != "+OK\r\n" {
+		t.Errorf("CLIENT REPLY OFF/SKIP 동안에는 응답이 없어야 함. got=%q", got)
+	}
+
+	if got := roundTrip(t, conn, reader, "CLIENT KILL TYPE master\r\n"); got != ":0\r\n" {
+		t.Errorf("마스터 연결이 없으면 0이어야 함. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "CLIENT KILL ID "+otherID+"\r\n"); got != ":1\r\n" {
+		t.Errorf("CLIENT KILL ID 응답이 다름. got=%q", got)
+	}
+	_ = other.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := otherReader.ReadByte(); err == nil {
+		t.Errorf("닫힌 연결에서는 읽을 수 없어야 함")
+	}
+	if got := roundTrip(t, conn, reader, "CLIENT KILL 1.2.3.4:5\r\n"); got != "-ERR No such client\r\n" {
+		t.Errorf("없는 주소 응답이 다름. got=%q", got)
+	}
+
+	// SKIPME no면 자기 자신도 응답을 보낸 뒤 닫습니다
+	if got := roundTrip(t, conn, reader, "CLIENT KILL USER default SKIPME no\r\n"); got != ":1\r\n" {
+		t.Errorf("CLIENT KILL SKIPME no 응답이 다름. got=%q", got)
+	}
+	if _, err := reader.ReadByte(); err == nil {
+		t.Errorf("자기 자신을 닫으면 연결이 끊겨야 함")
+	}
+}
+
+func TestClientPause(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	admin, adminReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	writer, writerReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	// WRITE 모드에서는 읽기 명령어는 바로 실행되고 쓰기 명령어는 풀릴 때까지 기다립니다
+	start := time.Now()
+	if got := roundTrip(t, admin, adminReader, "CLIENT PAUSE 200 WRITE\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT PAUSE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, writer, writerReader, "GET k\r\n"); got != "$-1\r\n" {
+		t.Errorf("GET 응답이 다름. got=%q", got)
+	}
+	if time.Since(start) >= 200*time.Millisecond {
+		t.Errorf("읽기 명령어는 멈추지 않아야 함")
+	}
+	if got := roundTrip(t, writer, writerReader, "SET k v\r\n"); got != "+OK\r\n" {
+		t.Errorf("SET 응답이 다름. got=%q", got)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("쓰기 명령어는 pause가 끝날 때까지 기다려야 함. elapsed=%v", elapsed)
+	}
+
+	// UNPAUSE는 기다리던 명령어를 바로 풀어 줍니다
+	if got := roundTrip(t, admin, adminReader, "CLIENT PAUSE 10000 WRITE\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT PAUSE 응답이 다름. got=%q", got)
+	}
+	_, _ = writer.Write([]byte("SET k v2\r\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	if got := roundTrip(t, admin, adminReader, "CLIENT LIST TYPE normal\r\n"); !strings.HasPrefix(got, "$") {
+		t.Fatalf("CLIENT LIST 응답이 다름. got=%q", got)
+	}
+	_, _ = adminReader.ReadString('\n')
+	if line, _ := adminReader.ReadString('\n'); !strings.Contains(line, "flags=b ") {
+		t.Errorf("기다리는 클라이언트는 b 플래그가 있어야 함. got=%q", line)
+	}
+	_, _ = adminReader.ReadString('\n')
+
+	if got := roundTrip(t, admin, adminReader, "CLIENT UNPAUSE\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT UNPAUSE 응답이 다름. got=%q", got)
+	}
+	_ = writer.SetReadDeadline(time.Now().Add(time.Second))
+	if got, err := writerReader.ReadString('\n'); got != "+OK\r\n" {
+		t.Errorf("UNPAUSE 뒤에 SET이 실행되어야 함. got=%q, err=%v", got, err)
+	}
+	if got := roundTrip(t, admin, adminReader, "GET k\r\n"); got != "$2\r\n" {
+		t.Errorf("GET 응답이 다름. got=%q", got)
+	}
+	_, _ = adminReader.ReadString('\n')
+
+	// 파이프라인에서 쓰기 명령어가 멈춰도 앞서 실행한 읽기 명령어의 응답은 바로 받아야 합니다
+	if got := roundTrip(t, admin, adminReader, "CLIENT PAUSE 10000 WRITE\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT PAUSE 응답이 다름. got=%q", got)
+	}
+	_, _ = writer.Write([]byte("GET k\r\nSET k v3\r\n"))
+	_ = writer.SetReadDeadline(time.Now().Add(time.Second))
+	if got, err := writerReader.ReadString('\n'); got != "$2\r\n" {
+		t.Fatalf("GET 응답이 pause 동안 붙잡혀 있음. got=%q, err=%v", got, err)
+	}
+	if got, _ := writerReader.ReadString('\n'); got != "v2\r\n" {
+		t.Errorf("GET 응답이 다름. got=%q", got)
+	}
+
+	if got := roundTrip(t, admin, adminReader, "CLIENT UNPAUSE\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CLIENT UNPAUSE 응답이 다름. got=%q", got)
+	}
+	_ = writer.SetReadDeadline(time.Now().Add(time.Second))
+	if got, err := writerReader.ReadString('\n'); got != "+OK\r\n" {
+		t.Errorf("UNPAUSE 뒤에 SET이 실행되어야 함. got=%q, err=%v", got, err)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.Timeout = 1
+	startTestServer(t, cfg)
+
+	idle, idleReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	blocked, blockedReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	if got := roundTrip(t, idle, idleReader, "PING\r\n"); got != "+PONG\r\n" {
+		t.Fatalf("PING 응답이 다름. got=%q", got)
+	}
+	_, _ = blocked.Write([]byte("BLPOP queue 0\r\n"))
+
+	// 유휴 연결은 timeout이 지나면 닫힙니다
+	_ = idle.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := idleReader.ReadByte(); err == nil {
+		t.Fatalf("유휴 연결이 닫혀야 함")
+	}
+
+	// BLPOP으로 기다리는 연결은 닫지 않습니다
+	admin, adminReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	if got := roundTrip(t, admin, adminReader, "RPUSH queue a\r\n"); got != ":1\r\n" {
+		t.Fatalf("RPUSH 응답이 다름. got=%q", got)
+	}
+	_ = blocked.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if got, err := blockedReader.ReadString('\n'); got != "*2\r\n" {
+		t.Errorf("블로킹 클라이언트는 timeout으로 닫히면 안 됨. got=%q, err=%v", got, err)
+	}
+
+	info := readInfo(t, admin, adminReader, "all")
+	for _, want := range []string{"connected_clients:2\r\n", "client_timeout_disconnections:1\r\n"} {
+		if !strings.Contains(info, want) {
+			t.Errorf("INFO에 %q가 있어야 함. got=%q", want, info)
+		}
+	}
+}
+
+func TestMaxClients(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.MaxClients = 2
+	startTestServer(t, cfg)
+
+	addr := "127.0.0.1:" + strconv.Itoa(cfg.Port)
+	var conns []net.Conn
+	var readers []*bufio.Reader
+	for i := 0; i < 2; i++ {
+		conn, reader := dialServer(t, addr)
+		conns = append(conns, conn)
+		readers = append(readers, reader)
+		if got := roundTrip(t, conn, reader, "PING\r\n"); got != "+PONG\r\n" {
+			t.Fatalf("PING 응답이 다름. got=%q", got)
+		}
+	}
+
+	rejected, rejectedReader := dialServer(t, addr)
+	_ = rejected.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if got, _ := rejectedReader.ReadString('\n'); got != "-ERR max number of clients reached\r\n" {
+		t.Errorf("maxclients를 넘은 연결은 거절해야 함. got=%q", got)
+	}
+	if _, err := rejectedReader.ReadByte(); err == nil {
+		t.Errorf("거절한 연결은 닫혀야 함")
+	}
+
+	if info := readInfo(t, conns[0], readers[0], "stats"); !strings.Contains(info, "rejected_connections:1\r\n") {
+		t.Errorf("INFO에 rejected_connections가 있어야 함. got=%q", info)
+	}
+}
+
+func TestKeyCommands(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	roundTrip(t, conn, reader, "SET str v\r\n")
+	roundTrip(t, conn, reader, "RPUSH list a b\r\n")
+	roundTrip(t, conn, reader, "XADD stream 1-1 f v\r\n")
+	_, _ = reader.ReadString('\n')
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"EXISTS str str list stream missing", ":4\r\n"},
+		{"TOUCH str missing", ":1\r\n"},
+		{"COPY list list2", ":1\r\n"},
+		{"COPY list list2", ":0\r\n"},
+		{"RPUSH list2 c", ":3\r\n"},
+		{"LLEN list", ":2\r\n"},
+		{"COPY stream list2 REPLACE", ":1\r\n"},
+		{"TYPE list2", "+stream\r\n"},
+		{"COPY str str", "-ERR source and destination objects are the same\r\n"},
+		{"COPY str other DB 16", "-ERR DB index is out of range\r\n"},
+		{"RENAME missing x", "-ERR no such key\r\n"},
+		{"RENAMENX str list", ":0\r\n"},
+		{"RENAME str renamed", "+OK\r\n"},
+		{"EXISTS str", ":0\r\n"},
+		{"DEL renamed list missing", ":2\r\n"},
+		{"UNLINK stream list2", ":2\r\n"},
+		{"EXISTS renamed list stream list2", ":0\r\n"},
+		{"DEL", "-ERR wrong number of arguments for 'del' command\r\n"},
+		{"EXISTS", "-ERR wrong number of arguments for 'exists' command\r\n"},
+		{"UNLINK", "-ERR wrong number of arguments for 'unlink' command\r\n"},
+		{"TOUCH", "-ERR wrong number of arguments for 'touch' command\r\n"},
+	}
+	for _, tt := range tests {
+		if got := roundTrip(t, conn, reader, tt.cmd+"\r\n"); got != tt.want {
+			t.Errorf("%s 응답이 다름. want=%q, got=%q", tt.cmd, tt.want, got)
+		}
+	}
+
+	// BLPOP으로 기다리는 키는 DEL로 깨어나지 않고, RENAME으로 리스트가 들어오면 꺼내 갑니다
+	waiter, waiterReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	if _, err := waiter.Write([]byte("BLPOP queue 0\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	roundTrip(t, conn, reader, "DEL queue\r\n")
+	roundTrip(t, conn, reader, "RPUSH pending job\r\n")
+	if got := roundTrip(t, conn, reader, "RENAME pending queue\r\n"); got != "+OK\r\n" {
+		t.Fatalf("RENAME 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, waiter, waiterReader, ""); got != "*2\r\n" {
+		t.Fatalf("BLPOP이 깨어나야 함. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "EXISTS queue\r\n"); got != ":0\r\n" {
+		t.Errorf("비게 된 리스트는 지워져야 함. got=%q", got)
+	}
+}
+
+func TestExpireCommands(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	roundTrip(t, conn, reader, "RPUSH list a\r\n")
+	at := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"TTL missing", ":-2\r\n"},
+		{"TTL list", ":-1\r\n"},
+		{"EXPIRE missing 10", ":0\r\n"},
+		{"EXPIRE list 100 XX", ":0\r\n"},
+		{"EXPIRE list 100 GT", ":0\r\n"},
+		{"EXPIRE list 100 NX", ":1\r\n"},
+		{"EXPIRE list 100 NX", ":0\r\n"},
+		{"TTL list", ":100\r\n"},
+		{"EXPIRE list 50 GT", ":0\r\n"},
+		{"EXPIRE list 50 XX LT", ":1\r\n"},
+		{"EXPIRE list 50 NX GT", "-ERR NX and XX, GT or LT options at the same time are not compatible\r\n"},
+		{"EXPIRE list 50 GT LT", "-ERR GT and LT options at the same time are not compatible\r\n"},
+		{"EXPIRE list abc", "-ERR value is not an integer or out of range\r\n"},
+		{"EXPIRE list 9223372036854775807", "-ERR invalid expire time in 'expire' command\r\n"},
+		{"EXPIREAT list " + at, ":1\r\n"},
+		{"EXPIRETIME list", ":" + at + "\r\n"},
+		{"RENAME list moved", "+OK\r\n"},
+		{"EXPIRETIME moved", ":" + at + "\r\n"},
+		{"PERSIST moved", ":1\r\n"},
+		{"PERSIST moved", ":0\r\n"},
+		{"PEXPIRETIME moved", ":-1\r\n"},
+		{"PEXPIRE moved 50", ":1\r\n"},
+	}
+	for _, tt := range tests {
+		if got := roundTrip(t, conn, reader, tt.cmd+"\r\n"); got != tt.want {
+			t.Errorf("%s 응답이 다름. want=%q, got=%q", tt.cmd, tt.want, got)
+		}
+	}
+
+	// 만료된 리스트는 모든 읽기 경로에서 없는 키로 보입니다
+	time.Sleep(100 * time.Millisecond)
+	for cmd, want := range map[string]string{
+		"LLEN moved":   ":0\r\n",
+		"TYPE moved":   "+none\r\n",
+		"EXISTS moved": ":0\r\n",
+		"PTTL moved":   ":-2\r\n",
+	} {
+		if got := roundTrip(t, conn, reader, cmd+"\r\n"); got != want {
+			t.Errorf("%s 응답이 다름. want=%q, got=%q", cmd, want, got)
+		}
+	}
+
+	// 이미 지난 시각을 주면 키를 바로 지웁니다
+	roundTrip(t, conn, reader, "SET str v\r\n")
+	if got := roundTrip(t, conn, reader, "PEXPIREAT str 1\r\n"); got != ":1\r\n" {
+		t.Errorf("PEXPIREAT 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "EXISTS str\r\n"); got != ":0\r\n" {
+		t.Errorf("지난 시각으로 만료시키면 키가 지워져야 함. got=%q", got)
+	}
+}
+
+func TestActiveExpire(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.Hz = 100
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	// 한 번도 읽지 않는 키도 능동 만료로 지워져야 합니다
+	for i := 0; i < 50; i++ {
+		roundTrip(t, conn, reader, fmt.Sprintf("SET session:%d v PX 20\r\n", i))
+	}
+	roundTrip(t, conn, reader, "SET keep v\r\n")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		info := readInfo(t, conn, reader, "stats")
+		if strings.Contains(info, "expired_keys:50\r\n") {
+			if !strings.Contains(info, "expired_stale_perc:") {
+				t.Errorf("INFO stats에 expired_stale_perc가 있어야 함. got=%q", info)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("만료된 키가 지워지지 않음. got=%q", info)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := roundTrip(t, conn, reader, "EXISTS keep\r\n"); got != ":1\r\n" {
+		t.Errorf("만료 시각이 없는 키는 남아 있어야 함. got=%q", got)
+	}
+}
+
+func TestKeysAndScan(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	for i := 0; i < 200; i++ {
+		roundTrip(t, conn, reader, fmt.Sprintf("SET user:%d v\r\n", i))
+	}
+	roundTrip(t, conn, reader, "RPUSH queue:1 a\r\n")
+	roundTrip(t, conn, reader, "SET hello v\r\n")
+	roundTrip(t, conn, reader, "SET hallo v\r\n")
+
+	keys := readStrings(t, reader, roundTrip(t, conn, reader, "KEYS h[ae]llo\r\n"))
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"hallo", "hello"}) {
+		t.Errorf("KEYS 결과가 다름. got=%v", keys)
+	}
+
+	// SCAN은 도중에 키가 늘어나도 처음부터 있던 키를 모두 돌려줍니다
+	seen := map[string]bool{}
+	cursor, added := "0", 0
+	for {
+		if got := roundTrip(t, conn, reader, "SCAN "+cursor+" MATCH user:* COUNT 20\r\n"); got != "*2\r\n" {
+			t.Fatalf("SCAN 응답이 다름. got=%q", got)
+		}
+		_, _ = reader.ReadString('\n')
+		line, _ := reader.ReadString('\n')
+		cursor = strings.TrimSpace(line)
+		for _, key := range readStrings(t, reader, mustReadLine(t, reader)) {
+			seen[key] = true
+		}
+		if cursor == "0" {
+			break
+		}
+		for i := 0; i < 30; i++ {
+			roundTrip(t, conn, reader, fmt.Sprintf("SET extra:%d v\r\n", added))
+			added++
+		}
+	}
+	for i := 0; i < 200; i++ {
+		if !seen["user:"+strconv.Itoa(i)] {
+			t.Errorf("SCAN이 user:%d를 돌려주지 않음", i)
+		}
+	}
+	for key := range seen {
+		if !strings.HasPrefix(key, "user:") {
+			t.Errorf("MATCH에 맞지 않는 키 %q를 돌려줌", key)
+		}
+	}
+
+	roundTrip(t, conn, reader, "SCAN 0 TYPE list COUNT 1000\r\n")
+	_, _ = reader.ReadString('\n')
+	_, _ = reader.ReadString('\n')
+	if keys := readStrings(t, reader, mustReadLine(t, reader)); !slices.Equal(keys, []string{"queue:1"}) {
+		t.Errorf("SCAN TYPE 결과가 다름. got=%v", keys)
+	}
+
+	for cmd, want := range map[string]string{
+		"SCAN abc":          "-ERR invalid cursor\r\n",
+		"SCAN 0 COUNT 0":    "-ERR syntax error\r\n",
+		"SCAN 0 MATCH":      "-ERR syntax error\r\n",
+		"SCAN 0 TYPE thing": "-ERR unknown type name 'thing'\r\n",
+	} {
+		if got := roundTrip(t, conn, reader, cmd+"\r\n"); got != want {
+			t.Errorf("%s 응답이 다름. want=%q, got=%q", cmd, want, got)
+		}
+	}
+}
+
+func TestDatabases(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	cfg.Databases = 4
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"SET a 0", "+OK\r\n"},
+		{"SELECT 1", "+OK\r\n"},
+		{"EXISTS a", ":0\r\n"},
+		{"SET a 1", "+OK\r\n"},
+		{"SET b 1", "+OK\r\n"},
+		{"EXPIRE b 100", ":1\r\n"},
+		{"DBSIZE", ":2\r\n"},
+		{"MOVE a 0", ":0\r\n"},
+		{"MOVE b 2", ":1\r\n"},
+		{"MOVE b 1", "-ERR source and destination objects are the same\r\n"},
+		{"COPY a a DB 3", ":1\r\n"},
+		{"SELECT 2", "+OK\r\n"},
+		{"TTL b", ":100\r\n"},
+		{"SWAPDB 2 3", "+OK\r\n"},
+		{"EXISTS b", ":0\r\n"},
+		{"GET a", "$1\r\n"},
+		{"SELECT 4", "-ERR DB index is out of range\r\n"},
+		{"SELECT x", "-ERR value is not an integer or out of range\r\n"},
+		{"SWAPDB x 0", "-ERR invalid first DB index\r\n"},
+		{"SWAPDB 0 9", "-ERR DB index is out of range\r\n"},
+		{"FLUSHDB NOW", "-ERR syntax error\r\n"},
+		{"FLUSHDB", "+OK\r\n"},
+		{"DBSIZE", ":0\r\n"},
+		{"SELECT 0", "+OK\r\n"},
+		{"DBSIZE", ":1\r\n"},
+		{"FLUSHALL ASYNC", "+OK\r\n"},
+		{"DBSIZE", ":0\r\n"},
+		{"SELECT 3", "+OK\r\n"},
+		{"DBSIZE", ":0\r\n"},
+	}
+	for _, tt := range tests {
+		got := roundTrip(t, conn, reader, tt.cmd+"\r\n")
+		if got != tt.want {
+			t.Errorf("%s 응답이 다름. want=%q, got=%q", tt.cmd, tt.want, got)
+		}
+		if strings.HasPrefix(got, "$") && got != "$-1\r\n" {
+			_, _ = reader.ReadString('\n')
+		}
+	}
+
+	if got := roundTrip(t, conn, reader, "CLIENT INFO\r\n"); !strings.HasPrefix(got, "$") {
+		t.Fatalf("CLIENT INFO 응답이 다름. got=%q", got)
+	}
+	if line, _ := reader.ReadString('\n'); !strings.Contains(line, " db=3 ") {
+		t.Errorf("CLIENT INFO에 db=3이 있어야 함. got=%q", line)
+	}
+}
+
+// 다른 DB에 쓴 명령어는 SELECT와 함께 복제되어 레플리카에서도 같은 DB에 들어가야 합니다
+func TestReplicationSelect(t *testing.T) {
+	masterConfig := config.Default()
+	masterConfig.Port = freePort(t)
+	startTestServer(t, masterConfig)
+
+	replicaConfig := config.Default()
+	replicaConfig.Port = freePort(t)
+	replicaConfig.ReplicaOf = "127.0.0.1 " + strconv.Itoa(masterConfig.Port)
+	startTestServer(t, replicaConfig)
+
+	masterConn, masterReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(masterConfig.Port))
+	replicaConn, replicaReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(replicaConfig.Port))
+
+	assertReplicated(t, masterConn, masterReader, replicaConn, replicaReader)
+
+	roundTrip(t, masterConn, masterReader, "SELECT 5\r\n")
+	roundTrip(t, masterConn, masterReader, "SET tenant v\r\n")
+	roundTrip(t, masterConn, masterReader, "SELECT 0\r\n")
+	roundTrip(t, masterConn, masterReader, "SET shared v\r\n")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for roundTrip(t, replicaConn, replicaReader, "EXISTS shared\r\n") != ":1\r\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("DB 0의 쓰기가 복제되지 않음")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := roundTrip(t, replicaConn, replicaReader, "EXISTS tenant\r\n"); got != ":0\r\n" {
+		t.Errorf("DB 5의 키가 레플리카의 DB 0에 들어감. got=%q", got)
+	}
+	roundTrip(t, replicaConn, replicaReader, "SELECT 5\r\n")
+	if got := roundTrip(t, replicaConn, replicaReader, "EXISTS tenant\r\n"); got != ":1\r\n" {
+		t.Errorf("DB 5의 키가 레플리카의 DB 5에 있어야 함. got=%q", got)
+	}
+}
+
+// 마스터가 접근하면서 지운 만료 키도 레플리카에 DEL로 전파되어야 합니다
+func TestReplicationLazyExpire(t *testing.T) {
+	masterConfig := config.Default()
+	masterConfig.Port = freePort(t)
+	// 능동 만료가 먼저 지우지 않도록 주기를 가장 길게 둡니다
+	masterConfig.Hz = 1
+	startTestServer(t, masterConfig)
+
+	replicaConfig := config.Default()
+	replicaConfig.Port = freePort(t)
+	replicaConfig.ReplicaOf = "127.0.0.1 " + strconv.Itoa(masterConfig.Port)
+	startTestServer(t, replicaConfig)
+
+	masterConn, masterReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(masterConfig.Port))
+	replicaConn, replicaReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(replicaConfig.Port))
+
+	assertReplicated(t, masterConn, masterReader, replicaConn, replicaReader)
+
+	roundTrip(t, masterConn, masterReader, "SET k v PX 20\r\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for roundTrip(t, replicaConn, replicaReader, "DBSIZE\r\n") != ":2\r\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("SET이 복제되지 않음")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := roundTrip(t, masterConn, masterReader, "GET k\r\n"); got != "$-1\r\n" {
+		t.Fatalf("만료된 키의 GET 응답이 다름. got=%q", got)
+	}
+	// 레플리카는 스스로 만료 키를 지우지 않으므로 DBSIZE가 줄어들면 마스터의 DEL을 받은 것입니다
+	deadline = time.Now().Add(5 * time.Second)
+	for roundTrip(t, replicaConn, replicaReader, "DBSIZE\r\n") != ":1\r\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("접근으로 지운 만료 키가 레플리카에 전파되지 않음")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// DEL도 만료된 키는 지운 수에 넣지 않고 expired로 지워 레플리카에 전파합니다
+	roundTrip(t, masterConn, masterReader, "SET k v PX 20\r\n")
+	deadline = time.Now().Add(5 * time.Second)
+	for roundTrip(t, replicaConn, replicaReader, "DBSIZE\r\n") != ":2\r\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("SET이 복제되지 않음")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got := roundTrip(t, masterConn, masterReader, "DEL k\r\n"); got != ":0\r\n" {
+		t.Errorf("만료된 키의 DEL 응답이 다름. got=%q", got)
+	}
+	if stats := readInfo(t, masterConn, masterReader, "stats"); !strings.Contains(stats, "expired_keys:2\r\n") {
+		t.Errorf("DEL로 지운 만료 키도 expired_keys에 세어야 함. stats=%q", stats)
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for roundTrip(t, replicaConn, replicaReader, "DBSIZE\r\n") != ":1\r\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("DEL로 지운 만료 키가 레플리카에 전파되지 않음")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestLazyFree(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	// 해제 비용은 quicklist 노드 수로 어림하므로 작은 원소로도 노드가 lazyfree 기준보다 많아지게 합니다
+	cfg.ListMaxListpackSize = 16
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	bigList := func(key string) {
+		t.Helper()
+		if got := roundTrip(t, conn, reader, "RPUSH "+key+strings.Repeat(" v", 2000)+"\r\n"); got != ":2000\r\n" {
+			t.Fatalf("RPUSH 응답이 다름. got=%q", got)
+		}
+	}
+	waitFreed := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			stats := readInfo(t, conn, reader, "stats")
+			memory := readInfo(t, conn, reader, "memory")
+			if strings.Contains(stats, fmt.Sprintf("lazyfreed_objects:%d\r\n", want)) &&
+				strings.Contains(memory, "lazyfree_pending_objects:0\r\n") {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("lazyfreed_objects가 %d가 되지 않음. stats=%q memory=%q", want, stats, memory)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// 작은 값은 UNLINK여도 바로 해제합니다
+	roundTrip(t, conn, reader, "SET small v\r\n")
+	roundTrip(t, conn, reader, "UNLINK small\r\n")
+	bigList("big")
+	if got := roundTrip(t, conn, reader, "UNLINK big\r\n"); got != ":1\r\n" {
+		t.Fatalf("UNLINK 응답이 다름. got=%q", got)
+	}
+	waitFreed(1)
+
+	// lazyfree-lazy-user-del이 꺼져 있으면 DEL은 바로 해제합니다
+	bigList("big")
+	roundTrip(t, conn, reader, "DEL big\r\n")
+	waitFreed(1)
+	roundTrip(t, conn, reader, "CONFIG SET lazyfree-lazy-user-del yes\r\n")
+	bigList("big")
+	roundTrip(t, conn, reader, "DEL big\r\n")
+	waitFreed(2)
+
+	for _, key := range []string{"a", "b", "c"} {
+		roundTrip(t, conn, reader, "SET "+key+" v\r\n")
+	}
+	if got := roundTrip(t, conn, reader, "FLUSHALL ASYNC\r\n"); got != "+OK\r\n" {
+		t.Fatalf("FLUSHALL ASYNC 응답이 다름. got=%q", got)
+	}
+	waitFreed(5)
+}
+
+func TestMaxMemory(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	usedMemory := func() int {
+		t.Helper()
+		for _, line := range strings.Split(readInfo(t, conn, reader, "memory"), "\r\n") {
+			if v, ok := strings.CutPrefix(line, "used_memory:"); ok {
+				n, _ := strconv.Atoi(v)
+				return n
+			}
+		}
+		t.Fatal("used_memory가 없음")
+		return 0
+	}
+
+	// 값을 넣고 빼면 사용량이 제자리로 돌아와야 합니다
+	roundTrip(t, conn, reader, "SET s v\r\n")
+	roundTrip(t, conn, reader, "RPUSH l a bb ccc\r\n")
+	roundTrip(t, conn, reader, "XADD x * f v\r\n")
+	mustReadLine(t, reader)
+	if used := usedMemory(); used <= 0 {
+		t.Fatalf("used_memory가 늘어야 함. got=%d", used)
+	}
+	roundTrip(t, conn, reader, "LPOP l 2\r\n")
+	mustReadLine(t, reader)
+	mustReadLine(t, reader)
+	mustReadLine(t, reader)
+	mustReadLine(t, reader)
+	roundTrip(t, conn, reader, "DEL s l x\r\n")
+	if used := usedMemory(); used != 0 {
+		t.Fatalf("모두 지운 뒤 used_memory가 0이어야 함. got=%d", used)
+	}
+
+	value := strings.Repeat("x", 100)
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory 2000\r\n")
+
+	// noeviction은 메모리를 늘리는 명령어만 거부합니다
+	oom := false
+	for i := 0; i < 50 && !oom; i++ {
+		oom = strings.HasPrefix(roundTrip(t, conn, reader, fmt.Sprintf("SET k%d %s\r\n", i, value)), "-OOM")
+	}
+	if !oom {
+		t.Fatal("noeviction에서 OOM 에러가 나야 함")
+	}
+	if got := roundTrip(t, conn, reader, "GET k0\r\n"); got != "$100\r\n" {
+		t.Fatalf("OOM 중에도 읽기는 되어야 함. got=%q", got)
+	}
+	mustReadLine(t, reader)
+	if got := roundTrip(t, conn, reader, "DEL k0\r\n"); got != ":1\r\n" {
+		t.Fatalf("OOM 중에도 DEL은 되어야 함. got=%q", got)
+	}
+	roundTrip(t, conn, reader, "FLUSHALL\r\n")
+
+	// volatile 정책은 만료 시각이 없는 키를 쫓아내지 않습니다
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory-policy volatile-ttl\r\n")
+	roundTrip(t, conn, reader, "SET keep "+value+" PX 1000000\r\n")
+	for i := 0; i < 50; i++ {
+		if got := roundTrip(t, conn, reader, fmt.Sprintf("SET k%d %s PX 10000\r\n", i, value)); got != "+OK\r\n" {
+			t.Fatalf("volatile-ttl에서 SET이 실패함. got=%q", got)
+		}
+	}
+	if got := roundTrip(t, conn, reader, "EXISTS keep k49\r\n"); got != ":2\r\n" {
+		t.Fatalf("만료가 가장 늦은 키와 방금 쓴 키는 남아야 함. got=%q", got)
+	}
+	if used := usedMemory(); used > 2000 {
+		t.Fatalf("used_memory가 maxmemory 아래여야 함. got=%d", used)
+	}
+	if !strings.Contains(readInfo(t, conn, reader, "stats"), "evicted_keys:") {
+		t.Fatal("INFO stats에 evicted_keys가 없음")
+	}
+	roundTrip(t, conn, reader, "FLUSHALL\r\n")
+	for i := 0; i < 30; i++ {
+		roundTrip(t, conn, reader, fmt.Sprintf("SET p%d %s\r\n", i, value))
+	}
+	if got := roundTrip(t, conn, reader, "SET p "+value+"\r\n"); !strings.HasPrefix(got, "-OOM") {
+		t.Fatalf("쫓아낼 volatile 키가 없으면 OOM이어야 함. got=%q", got)
+	}
+
+	// allkeys 정책은 어떤 키든 쫓아내서 계속 씁니다
+	for _, policy := range []string{"allkeys-lru", "allkeys-lfu", "allkeys-random"} {
+		roundTrip(t, conn, reader, "CONFIG SET maxmemory-policy "+policy+"\r\n")
+		for i := 0; i < 50; i++ {
+			if got := roundTrip(t, conn, reader, fmt.Sprintf("SET %s%d %s\r\n", policy, i, value)); got != "+OK\r\n" {
+				t.Fatalf("%s에서 SET이 실패함. got=%q", policy, got)
+			}
+		}
+		if used := usedMemory(); used > 2000 {
+			t.Fatalf("%s: used_memory가 maxmemory 아래여야 함. got=%d", policy, used)
+		}
+	}
+}
+
+func TestObjectAndMemory(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	roundTrip(t, conn, reader, "SET n 123\r\n")
+	roundTrip(t, conn, reader, "SET s hello\r\n")
+	roundTrip(t, conn, reader, "SET r "+strings.Repeat("x", 50)+"\r\n")
+	// 기본값(-2)에서는 8KB를 넘어야 quicklist입니다
+	roundTrip(t, conn, reader, "RPUSH l"+strings.Repeat(" v", 11)+"\r\n")
+	roundTrip(t, conn, reader, "RPUSH q"+strings.Repeat(" "+strings.Repeat("v", 4000), 3)+"\r\n")
+	roundTrip(t, conn, reader, "XADD x * f v\r\n")
+	mustReadLine(t, reader)
+
+	objectEncoding := func(key string) string {
+		t.Helper()
+		got := roundTrip(t, conn, reader, "OBJECT ENCODING "+key+"\r\n")
+		if !strings.HasPrefix(got, "$") {
+			t.Fatalf("OBJECT ENCODING %s 응답이 다름. got=%q", key, got)
+		}
+		return strings.TrimSpace(mustReadLine(t, reader))
+	}
+	for key, want := range map[string]string{"n": "int", "s": "embstr", "r": "raw", "l": "listpack", "q": "quicklist", "x": "stream"} {
+		if got := objectEncoding(key); got != want {
+			t.Errorf("OBJECT ENCODING %s가 다름. got=%q want=%q", key, got, want)
+		}
+	}
+
+	// 양수 list-max-listpack-size는 원소 수 제한이라 128개까지 listpack, 129개부터 quicklist입니다
+	roundTrip(t, conn, reader, "CONFIG SET list-max-listpack-size 128\r\n")
+	roundTrip(t, conn, reader, "RPUSH b"+strings.Repeat(" v", 128)+"\r\n")
+	if got := objectEncoding("b"); got != "listpack" {
+		t.Errorf("원소 128개는 listpack이어야 함. got=%q", got)
+	}
+	roundTrip(t, conn, reader, "RPUSH b v\r\n")
+	if got := objectEncoding("b"); got != "quicklist" {
+		t.Errorf("원소 129개는 quicklist여야 함. got=%q", got)
+	}
+	roundTrip(t, conn, reader, "CONFIG SET list-max-listpack-size -2\r\n")
+	if got := roundTrip(t, conn, reader, "OBJECT ENCODING missing\r\n"); got != "$-1\r\n" {
+		t.Errorf("없는 키는 nil이어야 함. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT REFCOUNT s\r\n"); got != ":1\r\n" {
+		t.Errorf("OBJECT REFCOUNT 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT IDLETIME s\r\n"); got != ":0\r\n" {
+		t.Errorf("OBJECT IDLETIME 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT FREQ s\r\n"); !strings.HasPrefix(got, "-ERR An LFU maxmemory policy is not selected") {
+		t.Errorf("LFU 정책이 아니면 OBJECT FREQ는 에러여야 함. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT ENCODING\r\n"); got != "-ERR wrong number of arguments for 'object|encoding' command\r\n" {
+		t.Errorf("인수 개수 에러가 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT HELP\r\n"); !strings.HasPrefix(got, "*") {
+		t.Fatalf("OBJECT HELP 응답이 다름. got=%q", got)
+	}
+	for i := 0; i < 15; i++ {
+		mustReadLine(t, reader)
+	}
+
+	// LFU 정책에서는 TOUCH로 접근할 때마다 카운터가 (로그 확률로) 오르고 OBJECT는 카운터를 바꾸지 않습니다
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory-policy allkeys-lfu\r\n")
+	if got := roundTrip(t, conn, reader, "OBJECT FREQ s\r\n"); got != ":5\r\n" {
+		t.Errorf("새 키의 LFU 카운터가 다름. got=%q", got)
+	}
+	for i := 0; i < 100; i++ {
+		roundTrip(t, conn, reader, "TOUCH s\r\n")
+	}
+	got := roundTrip(t, conn, reader, "OBJECT FREQ s\r\n")
+	if freq, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(got, ":"))); freq <= 5 {
+		t.Errorf("TOUCH 뒤에는 LFU 카운터가 올라야 함. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT IDLETIME s\r\n"); !strings.HasPrefix(got, "-ERR An LFU maxmemory policy is selected") {
+		t.Errorf("LFU 정책에서 OBJECT IDLETIME은 에러여야 함. got=%q", got)
+	}
+
+	if got := roundTrip(t, conn, reader, "MEMORY USAGE s\r\n"); !strings.HasPrefix(got, ":") || got == ":0\r\n" {
+		t.Errorf("MEMORY USAGE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "MEMORY USAGE missing\r\n"); got != "$-1\r\n" {
+		t.Errorf("없는 키의 MEMORY USAGE는 nil이어야 함. got=%q", got)
+	}
+	all := roundTrip(t, conn, reader, "MEMORY USAGE q SAMPLES 0\r\n")
+	if sampled := roundTrip(t, conn, reader, "MEMORY USAGE q SAMPLES 5\r\n"); sampled != all {
+		t.Errorf("같은 크기 원소의 리스트는 표본과 전체 어림값이 같아야 함. got=%q want=%q", sampled, all)
+	}
+	if got := roundTrip(t, conn, reader, "MEMORY USAGE q SAMPLES -1\r\n"); got != "-ERR syntax error\r\n" {
+		t.Errorf("음수 SAMPLES는 에러여야 함. got=%q", got)
+	}
+
+	header := roundTrip(t, conn, reader, "MEMORY STATS\r\n")
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil || n%2 != 0 {
+		t.Fatalf("MEMORY STATS 응답이 다름. got=%q", header)
+	}
+	if got := mustReadLine(t, reader) + mustReadLine(t, reader); got != "$14\r\npeak.allocated\r\n" {
+		t.Errorf("MEMORY STATS 첫 항목이 다름. got=%q", got)
+	}
+	// 나머지는 PING으로 응답 끝을 찾아 버립니다
+	fmt.Fprint(conn, "PING\r\n")
+	for line := mustReadLine(t, reader); line != "+PONG\r\n"; line = mustReadLine(t, reader) {
+	}
+
+	doctor := roundTrip(t, conn, reader, "MEMORY DOCTOR\r\n")
+	if !strings.HasPrefix(doctor, "$") {
+		t.Fatalf("MEMORY DOCTOR 응답이 다름. got=%q", doctor)
+	}
+	if got := mustReadLine(t, reader); !strings.HasPrefix(got, "Hi Sam, this instance is empty") {
+		t.Errorf("작은 인스턴스는 진단하지 않아야 함. got=%q", got)
+	}
+}
+
+func TestKeyspaceNotifications(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+	sub, subReader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	// readMessage는 구독 연결로 온 message(3개)나 pmessage(4개) 배열을 읽습니다
+	readMessage := func() []string {
+		t.Helper()
+		_ = sub.SetDeadline(time.Now().Add(5 * time.Second))
+		return readStrings(t, subReader, mustReadLine(t, subReader))
+	}
+
+	// subscribeReply는 [p]subscribe, [p]unsubscribe 응답(종류, 이름, 구독 수)을 한 줄로 읽습니다
+	subscribeReply := func(cmd string) string {
+		t.Helper()
+		header := roundTrip(t, sub, subReader, cmd)
+		if header != "*3\r\n" {
+			t.Fatalf("%q 응답이 다름. got=%q", cmd, header)
+		}
+		kind := readStrings(t, subReader, "*1")
+		name := readStrings(t, subReader, "*1")
+		return kind[0] + " " + name[0] + " " + strings.TrimSpace(mustReadLine(t, subReader))
+	}
+
+	if got := subscribeReply("SUBSCRIBE news\r\n"); got != "subscribe news :1" {
+		t.Fatalf("SUBSCRIBE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, sub, subReader, "GET k\r\n"); !strings.HasPrefix(got, "-ERR Can't execute 'get'") {
+		t.Errorf("구독 중에는 GET을 거부해야 함. got=%q", got)
+	}
+	if got := roundTrip(t, sub, subReader, "PING\r\n") + mustReadLine(t, subReader) + mustReadLine(t, subReader) + mustReadLine(t, subReader) + mustReadLine(t, subReader); got != "*2\r\n$4\r\npong\r\n$0\r\n\r\n" {
+		t.Errorf("구독 중 PING 응답이 다름. got=%q", got)
+	}
+
+	if got := roundTrip(t, conn, reader, "PUBLISH news hello\r\n"); got != ":1\r\n" {
+		t.Fatalf("PUBLISH 응답이 다름. got=%q", got)
+	}
+	if got := readMessage(); !slices.Equal(got, []string{"message", "news", "hello"}) {
+		t.Fatalf("받은 메시지가 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "PUBSUB NUMSUB news other\r\n"); got != "*4\r\n" {
+		t.Fatalf("PUBSUB NUMSUB 응답이 다름. got=%q", got)
+	}
+	if got := strings.Join([]string{mustReadLine(t, reader), mustReadLine(t, reader), mustReadLine(t, reader), mustReadLine(t, reader), mustReadLine(t, reader), mustReadLine(t, reader)}, ""); got != "$4\r\nnews\r\n:1\r\n$5\r\nother\r\n:0\r\n" {
+		t.Errorf("PUBSUB NUMSUB 값이 다름. got=%q", got)
+	}
+
+	clientList := roundTrip(t, conn, reader, "CLIENT LIST\r\n")
+	n, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(clientList, "$")))
+	body := make([]byte, n+2)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "flags=P db=0 sub=1 psub=0") {
+		t.Errorf("CLIENT LIST에 구독 정보가 있어야 함. got=%q", body)
+	}
+
+	// 키 공간 알림은 설정하기 전에는 보내지 않습니다
+	if got := subscribeReply("PSUBSCRIBE __key*@0__:*\r\n"); got != "psubscribe __key*@0__:* :2" {
+		t.Fatalf("PSUBSCRIBE 응답이 다름. got=%q", got)
+	}
+	roundTrip(t, conn, reader, "SET quiet v\r\n")
+	if got := roundTrip(t, conn, reader, "CONFIG SET notify-keyspace-events KEA$\r\n"); got != "+OK\r\n" {
+		t.Fatalf("CONFIG SET 응답이 다름. got=%q", got)
+	}
+	if got := readStrings(t, reader, roundTrip(t, conn, reader, "CONFIG GET notify-keyspace-events\r\n")); !slices.Equal(got, []string{"notify-keyspace-events", "AKE"}) {
+		t.Errorf("CONFIG GET 값이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "CONFIG SET notify-keyspace-events Q\r\n"); !strings.Contains(got, "Invalid event class character") {
+		t.Errorf("잘못된 플래그는 에러여야 함. got=%q", got)
+	}
+
+	expect := func(want ...string) {
+		t.Helper()
+		if got := readMessage(); !slices.Equal(got, want) {
+			t.Fatalf("알림이 다름. got=%q want=%q", got, want)
+		}
+	}
+	roundTrip(t, conn, reader, "SET k v\r\n")
+	expect("pmessage", "__key*@0__:*", "__keyspace@0__:k", "set")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:set", "k")
+	roundTrip(t, conn, reader, "RPUSH l a\r\n")
+	expect("pmessage", "__key*@0__:*", "__keyspace@0__:l", "rpush")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:rpush", "l")
+	roundTrip(t, conn, reader, "LPOP l\r\n")
+	mustReadLine(t, reader)
+	expect("pmessage", "__key*@0__:*", "__keyspace@0__:l", "lpop")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:lpop", "l")
+	expect("pmessage", "__key*@0__:*", "__keyspace@0__:l", "del")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:del", "l")
+
+	// 키 이벤트만 켜고 만료와 새 키 알림을 확인합니다
+	roundTrip(t, conn, reader, "CONFIG SET notify-keyspace-events Exn\r\n")
+	roundTrip(t, conn, reader, "SET tmp v PX 50\r\n")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:new", "tmp")
+	expect("pmessage", "__key*@0__:*", "__keyevent@0__:expired", "tmp")
+
+	// maxmemory를 넘겨 쫓아낸 키도 알립니다 (big 하나만 남기고 다음 명령어 전에 쫓아냅니다)
+	roundTrip(t, conn, reader, "FLUSHALL\r\n")
+	roundTrip(t, conn, reader, "CONFIG SET notify-keyspace-events Ee\r\n")
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory-policy allkeys-random\r\n")
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory 1\r\n")
+	roundTrip(t, conn, reader, "SET big v\r\n")
+	roundTrip(t, conn, reader, "CONFIG SET maxmemory 0\r\n")
+	if got := readMessage(); !slices.Equal(got, []string{"pmessage", "__key*@0__:*", "__keyevent@0__:evicted", "big"}) {
+		t.Fatalf("evicted 알림이 와야 함. got=%q", got)
+	}
+
+	if got := subscribeReply("UNSUBSCRIBE\r\n"); got != "unsubscribe news :1" {
+		t.Fatalf("UNSUBSCRIBE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "PUBSUB NUMPAT\r\n"); got != ":1\r\n" {
+		t.Errorf("PUBSUB NUMPAT 응답이 다름. got=%q", got)
+	}
+}
+
+func TestDumpRestore(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = freePort(t)
+	startTestServer(t, cfg)
+
+	conn, reader := dialServer(t, "127.0.0.1:"+strconv.Itoa(cfg.Port))
+
+	// dump는 DUMP 응답의 페이로드를 읽습니다 (바이너리라 줄 단위로 읽지 않습니다)
+	dump := func(key string) []byte {
+		t.Helper()
+		header := roundTrip(t, conn, reader, "DUMP "+key+"\r\n")
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil || n <= 0 {
+			t.Fatalf("DUMP 응답이 다름. got=%q", header)
+		}
+		body := make([]byte, n+2)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			t.Fatal(err)
+		}
+		return body[:n]
+	}
+	restore := func(key, ttl string, payload []byte, opts ...string) string {
+		t.Helper()
+		args := append([]string{"RESTORE", key, ttl, string(payload)}, opts...)
+		msg := fmt.Sprintf("*%d\r\n", len(args))
+		for _, arg := range args {
+			msg += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+		}
+		return roundTrip(t, conn, reader, msg)
+	}
+
+	if got := roundTrip(t, conn, reader, "DUMP missing\r\n"); got != "$-1\r\n" {
+		t.Errorf("없는 키의 DUMP는 nil이어야 함. got=%q", got)
+	}
+
+	roundTrip(t, conn, reader, "SET s hello\r\n")
+	roundTrip(t, conn, reader, "RPUSH l a 1 bb\r\n")
+	roundTrip(t, conn, reader, "XADD x 1-1 f v\r\n")
+	mustReadLine(t, reader)
+	for _, key := range []string{"s", "l", "x"} {
+		payload := dump(key)
+		if got := restore(key, "0", payload); got != "-BUSYKEY Target key name already exists.\r\n" {
+			t.Errorf("있는 키에 RESTORE하면 BUSYKEY여야 함. got=%q", got)
+		}
+		if got := restore(key+"2", "0", payload); got != "+OK\r\n" {
+			t.Fatalf("RESTORE %s 응답이 다름. got=%q", key, got)
+		}
+		if got := dump(key + "2"); string(got) != string(payload) {
+			t.Errorf("되돌린 %s의 DUMP가 같아야 함", key)
+		}
+	}
+	if got := roundTrip(t, conn, reader, "LRANGE l2 0 -1\r\n"); got != "*3\r\n" {
+		t.Fatalf("LRANGE 응답이 다름. got=%q", got)
+	}
+	if got := readStrings(t, reader, "*3"); !slices.Equal(got, []string{"a", "1", "bb"}) {
+		t.Errorf("되돌린 리스트가 다름. got=%q", got)
+	}
+
+	payload := dump("s")
+	if got := restore("s", "0", payload, "REPLACE", "IDLETIME", "1000"); got != "+OK\r\n" {
+		t.Fatalf("RESTORE REPLACE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "OBJECT IDLETIME s\r\n"); got != ":1000\r\n" {
+		t.Errorf("IDLETIME이 반영되어야 함. got=%q", got)
+	}
+	if got := restore("t", "100000", payload); got != "+OK\r\n" {
+		t.Fatalf("TTL을 준 RESTORE 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "PTTL t\r\n"); got == ":-1\r\n" || !strings.HasPrefix(got, ":") {
+		t.Errorf("TTL이 있어야 함. got=%q", got)
+	}
+	if got := restore("s", "1", payload, "REPLACE", "ABSTTL"); got != "+OK\r\n" {
+		t.Fatalf("지난 ABSTTL 응답이 다름. got=%q", got)
+	}
+	if got := roundTrip(t, conn, reader, "EXISTS s\r\n"); got != ":0\r\n" {
+		t.Errorf("이미 지난 만료 시각이면 키가 지워져야 함. got=%q", got)
+	}
+
+	bad := append([]byte(nil), payload...)
+	bad[len(bad)-1] ^= 0xff
+	if got := restore("u", "0", bad); got != "-ERR DUMP payload version or checksum are wrong\r\n" {
+		t.Errorf("체크섬이 틀리면 거부해야 함. got=%q", got)
+	}
+	if got := restore("u", "-1", payload); got != "-ERR Invalid TTL value, must be >= 0\r\n" {
+		t.Errorf("음수 TTL은 거부해야 함. got=%q", got)
+	}
+	if got := restore("u", "0", payload, "IDLETIME", "1", "FREQ", "1"); got != "-ERR syntax error\r\n" {
+		t.Errorf("IDLETIME과 FREQ를 함께 쓰면 거부해야 함. got=%q", got)
+	}
+	if got := restore("u", "0", payload, "FREQ", "256"); got != "-ERR Invalid FREQ value, must be >= 0 and <= 255\r\n" {
+		t.Errorf("범위를 넘는 FREQ는 거부해야 함. got=%q", got)
+	}
+}
+
+func mustReadLine(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("읽기 실패: %v", err)
+	}
+	return line
+}
+
+// readStrings는 header("*N\r\n") 뒤에 오는 벌크 문자열 N개를 읽습니다
+func readStrings(t *testing.T, reader *bufio.Reader, header string) []string {
+	t.Helper()
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		t.Fatalf("배열 응답이 아님. got=%q", header)
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		mustReadLine(t, reader)
+		out = append(out, strings.TrimSpace(mustReadLine(t, reader)))
+	}
+	return out
+}
+
+// readInfo는 INFO section을 보내고 벌크 문자열 응답 전체를 읽습니다
+func readInfo(t *testing.T, conn net.Conn, reader *bufio.Reader, section string) string {
+	t.Helper()
+	header := roundTrip(t, conn, reader, "INFO "+section+"\r\n")
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+	if err != nil {
+		t.Fatalf("INFO 응답이 다름. got=%q", header)
+	}
+	body := make([]byte, n+2)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		t.Fatal(err)
+	}
+	return string(body[:n])
+}
+
+// dialServer는 addr에 TCP로 접속하고 응답을 읽을 리더를 함께 반환합니다 (연결은 테스트가 끝나면 닫습니다)
+func dialServer(t *testing.T, addr string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, bufio.NewReader(conn)
+}
@@ -0,0 +1,298 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/store/dict"
+)
+
+// shared는 모든 논리 데이터베이스가 함께 쓰는 상태입니다
+type shared struct {
+	// expirePausedUntil까지는 만료된 키를 없는 것으로 보기만 하고 지우지 않습니다 (CLIENT PAUSE, unix 나노초)
+	expirePausedUntil atomic.Int64
+
+	// 만료 통계 (INFO stats의 expired_keys, expired_stale_perc)
+	expiredKeys atomic.Int64
+	stalePerc   atomic.Uint64 // float64 비트
+
+	// lazyExpired는 접근할 때 지운 키를 DB 번호별로 모아 둡니다 (레플리카에 DEL로 전파할 때까지, lazyExpiredMu로 보호)
+	lazyExpiredMu sync.Mutex
+	lazyExpired   map[int][]string
+
+	// lazyfree 설정과 통계 (lazyfree.go)
+	lazyfree        atomic.Pointer[LazyFree]
+	lazyfreePending atomic.Int64
+	lazyfreed       atomic.Int64
+
+	// maxmemory 설정과 메모리 사용량 어림값, 축출 통계 (evict.go)
+	eviction    atomic.Pointer[Eviction]
+	usedMemory  atomic.Int64
+	peakMemory  atomic.Int64
+	evictedKeys atomic.Int64
+
+	// 키 공간 알림 설정과 알림을 보낼 pub/sub (notify.go)
+	notifyFlags atomic.Int64
+	publish     atomic.Pointer[Publisher]
+
+	// 자료구조 인코딩 기준 (encoding.go)
+	encoding atomic.Pointer[Encoding]
+}
+
+func (sh *shared) expirationPaused() bool {
+	return time.Now().UnixNano() < sh.expirePausedUntil.Load()
+}
+
+func (sh *shared) addLazyExpired(db int, key string) {
+	sh.lazyExpiredMu.Lock()
+	defer sh.lazyExpiredMu.Unlock()
+	if sh.lazyExpired == nil {
+		sh.lazyExpired = make(map[int][]string)
+	}
+	sh.lazyExpired[db] = append(sh.lazyExpired[db], key)
+}
+
+// Databases는 databases 설정 수만큼의 논리 데이터베이스입니다
+type Databases struct {
+	dbs    []*Store
+	shared *shared
+
+	// nextExpireDB는 능동 만료가 다음에 볼 DB입니다 (이벤트 루프에서만 씁니다)
+	nextExpireDB int
+
+	// 축출 후보 풀과 random 정책이 다음에 볼 DB입니다 (이벤트 루프에서만 씁니다)
+	pool        []evictionCandidate
+	nextEvictDB int
+}
+
+func NewDatabases(n int) *Databases {
+	d := &Databases{dbs: make([]*Store, n), shared: &shared{}}
+	for i := range d.dbs {
+		d.dbs[i] = newStore(d.shared, i)
+	}
+	return d
+}
+
+func (d *Databases) Len() int {
+	return len(d.dbs)
+}
+
+// DB는 index번 데이터베이스입니다 (index는 SELECT에서 범위를 검사한 값이어야 합니다)
+func (d *Databases) DB(index int) *Store {
+	return d.dbs[index]
+}
+
+// lockPair는 두 데이터베이스의 쓰기 잠금을 번호 순서대로 잡고 푸는 함수를 반환합니다
+func (d *Databases) lockPair(a, b int) func() {
+	if a == b {
+		d.dbs[a].mu.Lock()
+		return d.dbs[a].mu.Unlock
+	}
+	first, second := d.dbs[min(a, b)], d.dbs[max(a, b)]
+	first.mu.Lock()
+	second.mu.Lock()
+	return func() {
+		second.mu.Unlock()
+		first.mu.Unlock()
+	}
+}
+
+// Copy는 srcDB의 src를 깊은 복사해 dstDB의 dst에 저장합니다 (만료 시각 포함)
+// replace가 아니면 dst가 이미 있을 때 복사하지 않고 false를 반환합니다
+func (d *Databases) Copy(srcDB int, src string, dstDB int, dst string, replace bool) (bool, error) {
+	if srcDB == dstDB && src == dst {
+		return false, fmt.Errorf("ERR source and destination objects are the same")
+	}
+	defer d.lockPair(srcDB, dstDB)()
+	from, to := d.dbs[srcDB], d.dbs[dstDB]
+
+	entry := from.get(src)
+	if entry == nil {
+		return false, nil
+	}
+	if !replace && to.get(dst) != nil {
+		return false, nil
+	}
+
+	to.setKey(dst, entry.Copy())
+	if at, ok := from.expires[src]; ok {
+		to.expires[dst] = at
+	}
+	to.signalKey(dst)
+	to.notify(config.NotifyGeneric, "copy_to", dst)
+	return true, nil
+}
+
+// Move는 srcDB의 key를 dstDB로 옮깁니다 (MOVE)
+// 키가 없거나 dstDB에 이미 같은 키가 있으면 false를 반환합니다
+func (d *Databases) Move(key string, srcDB, dstDB int) (bool, error) {
+	if srcDB == dstDB {
+		return false, fmt.Errorf("ERR source and destination objects are the same")
+	}
+	defer d.lockPair(srcDB, dstDB)()
+	from, to := d.dbs[srcDB], d.dbs[dstDB]
+
+	if from.get(key) == nil || to.get(key) != nil {
+		return false, nil
+	}
+
+	at, hasTTL := from.expires[key]
+	to.putObject(key, from.removeKey(key))
+	if hasTTL {
+		to.expires[key] = at
+	}
+	to.signalKey(key)
+	from.notify(config.NotifyGeneric, "move_from", key)
+	to.notify(config.NotifyGeneric, "move_to", key)
+	return true, nil
+}
+
+// Swap은 두 데이터베이스의 내용을 바꿉니다 (SWAPDB)
+// 각 DB에 연결된 클라이언트와 기다리는 클라이언트는 그대로 남고 데이터만 바뀌므로, 기다리던 키를 모두 다시 확인하게 합니다
+func (d *Databases) Swap(a, b int) {
+	if a == b {
+		return
+	}
+	defer d.lockPair(a, b)()
+	x, y := d.dbs[a], d.dbs[b]
+	x.items, y.items = y.items, x.items
+	x.expires, y.expires = y.expires, x.expires
+	x.used, y.used = y.used, x.used
+	for _, db := range []*Store{x, y} {
+		for key := range db.waiters {
+			db.signalKey(key)
+		}
+	}
+}
+
+// Flush는 index번 데이터베이스를 비웁니다 (FLUSHDB, async면 값은 백그라운드에서 해제합니다)
+func (d *Databases) Flush(index int, async bool) {
+	d.dbs[index].flush(async)
+}
+
+// FlushAll은 모든 데이터베이스를 비웁니다 (FLUSHALL)
+func (d *Databases) FlushAll(async bool) {
+	for _, db := range d.dbs {
+		db.flush(async)
+	}
+}
+
+// flush는 키와 만료 인덱스를 새 테이블로 바꿉니다. 기다리는 클라이언트는 그대로 기다립니다
+func (store *Store) flush(async bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	old := store.items
+	store.items = dict.New[*object]()
+	store.expires = make(map[string]time.Time)
+	store.account(-store.used)
+	if async {
+		store.shared.freeDict(old)
+	}
+}
+
+// Size는 키 수입니다 (DBSIZE, 아직 지우지 않은 만료된 키도 셉니다)
+func (store *Store) Size() int {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.items.Len()
+}
+
+// KeyspaceInfo는 INFO keyspace의 "dbN:keys=..,expires=..,avg_ttl=.." 줄들입니다 (빈 DB는 뺍니다)
+func (d *Databases) KeyspaceInfo() []string {
+	var lines []string
+	for i, db := range d.dbs {
+		db.mu.RLock()
+		keys, expires := db.items.Len(), len(db.expires)
+		var ttlSum time.Duration
+		now := time.Now()
+		for _, at := range db.expires {
+			if at.After(now) {
+				ttlSum += at.Sub(now)
+			}
+		}
+		db.mu.RUnlock()
+
+		if keys == 0 {
+			continue
+		}
+		avgTTL := int64(0)
+		if expires > 0 {
+			avgTTL = ttlSum.Milliseconds() / int64(expires)
+		}
+		lines = append(lines, fmt.Sprintf("db%d:keys=%d,expires=%d,avg_ttl=%d", i, keys, expires, avgTTL))
+	}
+	return lines
+}
+
+// PauseExpiration은 until까지 만료된 키를 지우지 않게 합니다 (zero value면 바로 풉니다)
+// CLIENT PAUSE 동안 데이터셋이 바뀌지 않아야 레플리카가 마스터를 따라잡을 수 있습니다
+func (d *Databases) PauseExpiration(until time.Time) {
+	if until.IsZero() {
+		d.shared.expirePausedUntil.Store(0)
+		return
+	}
+	d.shared.expirePausedUntil.Store(until.UnixNano())
+}
+
+// expireDBsPerCall은 능동 만료가 한 번에 보는 최대 DB 수입니다 (Redis CRON_DBS_PER_CALL)
+const expireDBsPerCall = 16
+
+// ActiveExpireCycle은 DB를 돌아가며 만료된 키를 지우고, 지운 키를 DB 번호별로 반환합니다 (Redis activeExpireCycle)
+// CLIENT PAUSE 중에는 데이터셋을 바꾸지 않도록 아무것도 하지 않습니다
+func (d *Databases) ActiveExpireCycle(samples int, budget time.Duration) map[int][]string {
+	if d.shared.expirationPaused() {
+		return nil
+	}
+
+	deadline := time.Now().Add(budget)
+	deleted := make(map[int][]string)
+	totalSampled, totalExpired, total := 0, 0, 0
+	for i := 0; i < min(len(d.dbs), expireDBsPerCall) && time.Now().Before(deadline); i++ {
+		index := d.nextExpireDB
+		d.nextExpireDB = (d.nextExpireDB + 1) % len(d.dbs)
+
+		keys, sampled, expired := d.dbs[index].activeExpire(samples, deadline)
+		if len(keys) > 0 {
+			deleted[index] = keys
+		}
+		totalSampled += sampled
+		totalExpired += expired
+		total += len(keys)
+	}
+
+	d.shared.expiredKeys.Add(int64(total))
+	if totalSampled > 0 {
+		// Redis와 같이 주기마다 구한 비율의 지수 이동 평균을 보고합니다
+		current := float64(totalExpired) / float64(totalSampled)
+		stale := math.Float64frombits(d.shared.stalePerc.Load())
+		d.shared.stalePerc.Store(math.Float64bits(current*0.05 + stale*0.95))
+	}
+	return deleted
+}
+
+// LazyExpired는 명령어가 접근하면서 지운 만료 키를 DB 번호별로 반환하고 비웁니다 (능동 만료로 지운 키는 ActiveExpireCycle이 반환합니다)
+func (d *Databases) LazyExpired() map[int][]string {
+	d.shared.lazyExpiredMu.Lock()
+	defer d.shared.lazyExpiredMu.Unlock()
+	deleted := d.shared.lazyExpired
+	d.shared.lazyExpired = nil
+	return deleted
+}
+
+// ExpireStats는 INFO stats의 expired_keys와 expired_stale_perc(백분율)를 반환합니다
+func (d *Databases) ExpireStats() (expiredKeys int64, stalePerc float64) {
+	return d.shared.expiredKeys.Load(), math.Float64frombits(d.shared.stalePerc.Load()) * 100
+}
+
+// ResetStats는 CONFIG RESETSTAT으로 만료, lazyfree, 축출 통계를 0으로 되돌립니다 (메모리 최고치는 지금 사용량으로)
+func (d *Databases) ResetStats() {
+	d.shared.peakMemory.Store(d.shared.usedMemory.Load())
+	d.shared.expiredKeys.Store(0)
+	d.shared.evictedKeys.Store(0)
+	d.shared.stalePerc.Store(0)
+	d.shared.lazyfreed.Store(0)
+}
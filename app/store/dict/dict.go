@@ -0,0 +1,144 @@
+package dict
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Dict는 SCAN 커서를 지원하는 해시 테이블입니다 (Redis dict와 같이 버킷 수가 2의 거듭제곱입니다)
+// Go map은 순회 위치를 커서로 돌려줄 수 없어서 따로 만듭니다
+type Dict[V any] struct {
+	table []*entry[V]
+	used  int
+	seed  maphash.Seed
+}
+
+type entry[V any] struct {
+	key   string
+	value V
+	next  *entry[V]
+}
+
+const initialSize = 4
+
+func New[V any]() *Dict[V] {
+	return &Dict[V]{seed: maphash.MakeSeed()}
+}
+
+func (d *Dict[V]) Len() int {
+	return d.used
+}
+
+func (d *Dict[V]) bucket(key string) int {
+	return int(maphash.String(d.seed, key) & uint64(len(d.table)-1))
+}
+
+func (d *Dict[V]) Get(key string) (V, bool) {
+	if d.used > 0 {
+		for e := d.table[d.bucket(key)]; e != nil; e = e.next {
+			if e.key == key {
+				return e.value, true
+			}
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Set은 key의 값을 저장합니다. 원소 수가 버킷 수에 이르면 테이블을 두 배로 늘립니다
+func (d *Dict[V]) Set(key string, value V) {
+	if len(d.table) == 0 {
+		d.resize(initialSize)
+	}
+	i := d.bucket(key)
+	for e := d.table[i]; e != nil; e = e.next {
+		if e.key == key {
+			e.value = value
+			return
+		}
+	}
+	d.table[i] = &entry[V]{key: key, value: value, next: d.table[i]}
+	d.used++
+	if d.used >= len(d.table) {
+		d.resize(len(d.table) * 2)
+	}
+}
+
+// Delete는 key를 지우고, 있었으면 true를 반환합니다. 버킷이 1/8 넘게 비면 테이블을 줄입니다
+func (d *Dict[V]) Delete(key string) bool {
+	if d.used == 0 {
+		return false
+	}
+	i := d.bucket(key)
+	for p := &d.table[i]; *p != nil; p = &(*p).next {
+		if (*p).key == key {
+			*p = (*p).next
+			d.used--
+			if len(d.table) > initialSize && d.used < len(d.table)/8 {
+				d.resize(len(d.table) / 2)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dict[V]) resize(size int) {
+	old := d.table
+	d.table = make([]*entry[V], size)
+	for _, head := range old {
+		for e := head; e != nil; {
+			next := e.next
+			i := d.bucket(e.key)
+			e.next = d.table[i]
+			d.table[i] = e
+			e = next
+		}
+	}
+}
+
+// Range는 모든 원소에 fn을 호출합니다. fn이 false를 반환하면 멈춥니다 (순회 중에 Dict를 바꾸면 안 됩니다)
+func (d *Dict[V]) Range(fn func(key string, value V) bool) {
+	for _, head := range d.table {
+		for e := head; e != nil; e = e.next {
+			if !fn(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Scan은 cursor가 가리키는 버킷의 원소에 fn을 호출하고 다음 커서를 반환합니다 (끝나면 0)
+// 커서의 높은 비트부터 올려 가는 역방향 이진 순서라서, 호출 사이에 테이블이 커지거나 줄어도
+// 순회 내내 남아 있는 키는 빠짐없이 돌려줍니다 (중복은 있을 수 있습니다)
+func (d *Dict[V]) Scan(cursor uint64, fn func(key string, value V)) uint64 {
+	if len(d.table) == 0 {
+		return 0
+	}
+	mask := uint64(len(d.table) - 1)
+	for e := d.table[cursor&mask]; e != nil; e = e.next {
+		fn(e.key, e.value)
+	}
+
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	return bits.Reverse64(cursor)
+}
+
+// Sample은 무작위 버킷부터 이어지는 버킷을 훑어 최대 n개 원소에 fn을 호출합니다 (Redis dictGetSomeKeys)
+// 고르게 뽑은 표본은 아니지만 maxmemory 축출처럼 근사로 충분한 곳에 씁니다 (호출 중에 Dict를 바꾸면 안 됩니다)
+func (d *Dict[V]) Sample(n int, fn func(key string, value V)) {
+	if d.used == 0 || n <= 0 {
+		return
+	}
+	mask := len(d.table) - 1
+	start := rand.IntN(len(d.table))
+	for i := 0; i < len(d.table) && n > 0; i++ {
+		for e := d.table[(start+i)&mask]; e != nil && n > 0; e = e.next {
+			fn(e.key, e.value)
+			n--
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package dict
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSetGetDelete(t *testing.T) {
+	d := New[int]()
+	for i := 0; i < 1000; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+	d.Set("7", 70)
+	if d.Len() != 1000 {
+		t.Fatalf("Len이 다름. got=%d", d.Len())
+	}
+	if v, ok := d.Get("7"); !ok || v != 70 {
+		t.Errorf("Get 결과가 다름. got=%d,%v", v, ok)
+	}
+	for i := 0; i < 990; i++ {
+		if !d.Delete(strconv.Itoa(i)) {
+			t.Fatalf("%d를 지우지 못함", i)
+		}
+	}
+	if d.Delete("0") {
+		t.Error("이미 지운 키는 false여야 함")
+	}
+	if _, ok := d.Get("995"); !ok || d.Len() != 10 {
+		t.Errorf("남은 키가 다름. len=%d", d.Len())
+	}
+	if len(d.table) > 64 {
+		t.Errorf("지운 뒤 테이블이 줄어야 함. got=%d", len(d.table))
+	}
+}
+
+// 순회 도중 테이블이 커지거나 줄어도 처음부터 끝까지 있던 키는 모두 돌려줘야 합니다
+func TestScanDuringResize(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		mutate func(d *Dict[int], step int)
+	}{
+		{"grow", func(d *Dict[int], step int) {
+			for i := 0; i < 50; i++ {
+				d.Set("new:"+strconv.Itoa(step)+":"+strconv.Itoa(i), 0)
+			}
+		}},
+		{"shrink", func(d *Dict[int], step int) {
+			for i := 0; i < 50; i++ {
+				d.Delete("tmp:" + strconv.Itoa(step*50+i))
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := New[int]()
+			for i := 0; i < 100; i++ {
+				d.Set("stable:"+strconv.Itoa(i), i)
+			}
+			for i := 0; i < 2000; i++ {
+				d.Set("tmp:"+strconv.Itoa(i), i)
+			}
+
+			seen := map[string]bool{}
+			cursor, step := uint64(0), 0
+			for {
+				cursor = d.Scan(cursor, func(key string, _ int) { seen[key] = true })
+				if cursor == 0 {
+					break
+				}
+				if step < 40 {
+					tc.mutate(d, step)
+				}
+				step++
+			}
+			for i := 0; i < 100; i++ {
+				if !seen["stable:"+strconv.Itoa(i)] {
+					t.Errorf("stable:%d를 돌려주지 않음", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	d := New[int]()
+	d.Sample(5, func(string, int) { t.Fatal("빈 Dict에서는 호출하지 않아야 함") })
+	for i := 0; i < 100; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+	seen := map[string]bool{}
+	d.Sample(5, func(key string, value int) {
+		if key != strconv.Itoa(value) || seen[key] {
+			t.Errorf("잘못된 표본. key=%s value=%d", key, value)
+		}
+		seen[key] = true
+	})
+	if len(seen) != 5 {
+		t.Errorf("표본 수가 다름. got=%d", len(seen))
+	}
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/rdb"
+)
+
+// Dump는 key의 값을 DUMP 페이로드로 직렬화합니다 (키가 없으면 false)
+func (store *Store) Dump(key string) ([]byte, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	entry := store.get(key)
+	if entry == nil {
+		return nil, false
+	}
+	return rdb.Dump(entry, store.shared.encodingPolicy().dumpLimits())
+}
+
+// RestoreOptions는 RESTORE의 옵션입니다
+type RestoreOptions struct {
+	ExpireAt time.Time // zero value면 만료 시각이 없습니다
+	Replace  bool
+	Idle     time.Duration // IDLETIME (마지막 접근 시각을 이만큼 앞당깁니다)
+	Freq     int           // FREQ, 음수면 새 키의 LFU 카운터를 씁니다
+}
+
+// Restore는 DUMP 페이로드를 값으로 되돌려 key에 저장합니다
+// 페이로드를 해석할 수 없으면 rdb.Restore의 에러를, Replace가 아니면서 키가 이미 있으면 BUSYKEY 에러를 반환합니다
+// 만료 시각이 이미 지났으면 저장하지 않고, Replace로 덮어쓸 키가 있었으면 지웁니다
+func (store *Store) Restore(key string, payload []byte, opts RestoreOptions) error {
+	value, err := rdb.Restore(payload, store.shared.encodingPolicy().dumpLimits())
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	exists := store.lookupWrite(key) != nil
+	if exists && !opts.Replace {
+		return fmt.Errorf("BUSYKEY Target key name already exists.")
+	}
+
+	if !opts.ExpireAt.IsZero() && !opts.ExpireAt.After(time.Now()) {
+		if exists {
+			store.deleteKey(key, store.shared.lazyfreePolicy().ServerDel)
+			store.notify(config.NotifyGeneric, "del", key)
+		}
+		return nil
+	}
+
+	obj := newObject(key, value)
+	obj.access.Store(time.Now().Add(-opts.Idle).UnixMilli())
+	if opts.Freq >= 0 {
+		obj.lfu.Store(lfuMinutes()<<8 | uint32(opts.Freq))
+	}
+	store.putObject(key, obj)
+	if !opts.ExpireAt.IsZero() {
+		store.expires[key] = opts.ExpireAt
+	}
+	store.signalKey(key)
+	store.notify(config.NotifyGeneric, "restore", key)
+	return nil
+}
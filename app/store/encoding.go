@@ -0,0 +1,39 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/rdb"
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity/list"
+)
+
+// Encoding은 자료구조 인코딩 기준 설정입니다
+type Encoding struct {
+	ListMaxListpackSize  int   // 양수면 listpack 하나의 원소 수, -1~-5면 4KB~64KB 크기 제한
+	StreamNodeMaxBytes   int64 // DUMP가 스트림 노드 하나에 담을 크기 (0이면 제한하지 않습니다)
+	StreamNodeMaxEntries int   // DUMP가 스트림 노드 하나에 담을 엔트리 수 (0이면 제한하지 않습니다)
+	ProtoMaxBulkLen      int64 // RESTORE가 압축을 풀어 만들 문자열 하나의 최대 크기
+}
+
+// defaultEncoding은 SetEncoding을 부르기 전에 쓰는 Redis 기본값입니다
+var defaultEncoding = Encoding{ListMaxListpackSize: list.DefaultFill, StreamNodeMaxBytes: 4096, StreamNodeMaxEntries: 100, ProtoMaxBulkLen: 512 * 1024 * 1024}
+
+// SetEncoding은 자료구조 인코딩 기준 설정을 적용합니다
+func (d *Databases) SetEncoding(enc Encoding) {
+	d.shared.encoding.Store(&enc)
+}
+
+func (sh *shared) encodingPolicy() Encoding {
+	if enc := sh.encoding.Load(); enc != nil {
+		return *enc
+	}
+	return defaultEncoding
+}
+
+// dumpLimits는 DUMP와 RESTORE가 리스트와 스트림을 노드로 나눌 기준입니다
+func (enc Encoding) dumpLimits() rdb.Limits {
+	return rdb.Limits{
+		ListMaxListpackSize:  enc.ListMaxListpackSize,
+		StreamNodeMaxBytes:   enc.StreamNodeMaxBytes,
+		StreamNodeMaxEntries: enc.StreamNodeMaxEntries,
+		MaxBulkLen:           enc.ProtoMaxBulkLen,
+	}
+}
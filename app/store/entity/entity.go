@@ -1,5 +1,41 @@
 package entity
 
+// Entity는 키 하나에 저장하는 값입니다
+// 만료 시각은 타입과 상관없이 Store의 expires에서 관리합니다
 type Entity interface {
-	Expired() bool
+	// Copy는 COPY 명령어에 쓰는 깊은 복사본을 만듭니다
+	Copy() Entity
+
+	// MemoryUsage는 값이 차지하는 메모리를 바이트 단위로 어림합니다 (maxmemory 계산에 씁니다)
+	MemoryUsage() int
+}
+
+// 메모리 사용량 어림에 쓰는 고정 비용입니다 (Redis 할당 크기를 대략 따릅니다)
+const (
+	stringOverhead      = 16 // robj와 sds 헤더
+	listOverhead        = 48 // quicklist와 첫 노드
+	listElementOverhead = 11 // listpack 엔트리 헤더와 backlen
+	streamOverhead      = 64
+	streamEntryOverhead = 32 // ID와 필드 슬라이스 헤더
+	streamFieldOverhead = 32 // 필드 이름과 값의 문자열 헤더
+)
+
+// SampledMemoryUsage는 리스트와 스트림을 앞쪽 samples개 원소의 평균으로 어림한 MemoryUsage입니다 (MEMORY USAGE SAMPLES)
+// samples가 0이거나 원소가 samples개 이하면 모두 셉니다
+func SampledMemoryUsage(e Entity, samples int) int {
+	switch v := e.(type) {
+	case *ListEntity:
+		if n := v.ValueData.Len(); samples > 0 && n > samples {
+			return listOverhead + ListElementsSize(v.ValueData.LRange(0, samples-1))*n/samples
+		}
+	case *StreamEntity:
+		if n := len(v.Entries); samples > 0 && n > samples {
+			size := 0
+			for _, entry := range v.Entries[:samples] {
+				size += StreamEntrySize(entry)
+			}
+			return streamOverhead + size*n/samples
+		}
+	}
+	return e.MemoryUsage()
 }
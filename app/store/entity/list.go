@@ -6,20 +6,29 @@ import (
 
 type ListEntity struct {
 	ValueData *list.QuickList
-	notify    chan struct{}
 }
 
-func (l ListEntity) Expired() bool {
-	return false
+func (l *ListEntity) Copy() Entity {
+	return &ListEntity{ValueData: l.ValueData.Clone()}
 }
 
-func (l ListEntity) Notify() chan struct{} {
-	return l.notify
+func (l *ListEntity) MemoryUsage() int {
+	return listOverhead + l.ValueData.Bytes() + l.ValueData.Len()*listElementOverhead
 }
 
-func NewListEntity() *ListEntity {
+// ListElementsSize는 리스트에 넣거나 꺼낸 원소들이 MemoryUsage에서 차지하는 크기입니다
+// 명령어마다 리스트 전체를 다시 세지 않도록 달라진 만큼만 더하고 뺄 때 씁니다
+func ListElementsSize(values [][]byte) int {
+	n := 0
+	for _, v := range values {
+		n += len(v) + listElementOverhead
+	}
+	return n
+}
+
+// NewListEntity는 fill(list-max-listpack-size) 크기로 노드를 나누는 빈 리스트를 만듭니다
+func NewListEntity(fill int) *ListEntity {
 	return &ListEntity{
-		ValueData: list.NewQuickList(),
-		notify:    make(chan struct{}, 1),
+		ValueData: list.NewQuickList(fill),
 	}
 }
@@ -86,6 +86,10 @@ func NewListpack(initialCap int) *Listpack {
 	return lp
 }
 
+func (lp *Listpack) Clone() *Listpack {
+	return &Listpack{buf: append(make([]byte, 0, cap(lp.buf)), lp.buf...)}
+}
+
 func (lp *Listpack) total() int {
 	return int(binary.LittleEndian.Uint32(lp.buf[0:4]))
 }
@@ -1,9 +1,21 @@
 package list
 
-const (
-	mergeLimit = 3
-	splitLimit = 10
-)
+const mergeLimit = 3
+
+// DefaultFill은 list-max-listpack-size 기본값입니다 (listpack 하나에 8KB까지)
+const DefaultFill = -2
+
+// sizeSafetyLimit은 원소 수로 제한할 때도 listpack 하나가 넘지 않게 하는 크기입니다 (Redis SIZE_SAFETY_LIMIT)
+const sizeSafetyLimit = 8192
+
+// NodeFits는 원소 count개, bytes 바이트인 listpack이 list-max-listpack-size(fill) 제한 안에 드는지 확인합니다
+// fill이 0 이상이면 원소 수(0이면 1개)로, -1~-5면 4KB~64KB 크기로 제한합니다 (Redis quicklistNodeExceedsLimit)
+func NodeFits(fill, count, bytes int) bool {
+	if fill >= 0 {
+		return count <= max(fill, 1) && bytes <= sizeSafetyLimit
+	}
+	return bytes <= 4096<<min(-fill-1, 4)
+}
 
 type quickNode struct {
 	prev *quickNode
@@ -11,10 +23,6 @@ type quickNode struct {
 	lp   *Listpack
 }
 
-func (n *quickNode) isSplitNeed() bool {
-	return n.lp.count() > splitLimit
-}
-
 func (n *quickNode) isMergeNeed() bool {
 	return n.lp.count() < mergeLimit
 }
@@ -31,11 +39,81 @@ type QuickList struct {
 	head *quickNode
 	tail *quickNode
 	size int
+
+	// fill은 만들 때의 list-max-listpack-size입니다 (노드를 나누고 합치는 기준, Redis와 같이 나중에 설정을 바꿔도 그대로입니다)
+	fill int
 }
 
-func NewQuickList() *QuickList {
+func NewQuickList(fill int) *QuickList {
 	n := newNode(nil, nil)
-	return &QuickList{head: n, tail: n, size: 0}
+	return &QuickList{head: n, tail: n, size: 0, fill: fill}
+}
+
+// isSplitNeed는 노드가 fill 제한을 넘었는지 확인합니다
+func (q *QuickList) isSplitNeed(n *quickNode) bool {
+	return !NodeFits(q.fill, n.count(), n.lp.total())
+}
+
+// canMerge는 두 노드를 합쳐도 fill 제한 안에 드는지 확인합니다
+func (q *QuickList) canMerge(a, b *quickNode) bool {
+	return NodeFits(q.fill, a.count()+b.count(), a.lp.total()+b.lp.total()-lpHeaderBytes-1)
+}
+
+// Clone은 노드와 listpack 버퍼까지 모두 복사한 새 리스트를 만듭니다
+func (q *QuickList) Clone() *QuickList {
+	c := &QuickList{size: q.size, fill: q.fill}
+	var prev *quickNode
+	for n := q.head; n != nil; n = n.next {
+		node := &quickNode{prev: prev, lp: n.lp.Clone()}
+		if prev == nil {
+			c.head = node
+		} else {
+			prev.next = node
+		}
+		prev = node
+	}
+	c.tail = prev
+	return c
+}
+
+// NodeCount는 노드 수를 셉니다. limit개를 넘으면 더 세지 않고 limit+1을 반환합니다
+func (q *QuickList) NodeCount(limit int) int {
+	n := 0
+	for node := q.head; node != nil && n <= limit; node = node.next {
+		n++
+	}
+	return n
+}
+
+// ListpackBytes는 모든 원소를 listpack 하나에 담았을 때의 크기입니다 (OBJECT ENCODING에 씁니다)
+func (q *QuickList) ListpackBytes() int {
+	n := lpHeaderBytes + 1
+	for node := q.head; node != nil; node = node.next {
+		n += node.lp.total() - lpHeaderBytes - 1
+	}
+	return n
+}
+
+// Bytes는 원소 값의 바이트 수를 모두 더합니다 (메모리 사용량 어림에 씁니다)
+func (q *QuickList) Bytes() int {
+	n := 0
+	for node := q.head; node != nil; node = node.next {
+		node.lp.scanForward(func(_, _, _ int, val []byte) bool {
+			n += len(val)
+			return true
+		})
+	}
+	return n
+}
+
+// Release는 노드 사이의 연결과 listpack 버퍼를 끊어 리스트를 비웁니다 (lazyfree 고루틴에서 호출)
+func (q *QuickList) Release() {
+	for n := q.head; n != nil; {
+		next := n.next
+		n.prev, n.next, n.lp = nil, nil, nil
+		n = next
+	}
+	q.head, q.tail, q.size = nil, nil, 0
 }
 
 func (q *QuickList) Len() int {
@@ -47,7 +125,7 @@ func (q *QuickList) RPush(value [][]byte) int {
 
 	for i := 0; i < len(value); i++ {
 		q.tail.lp.AppendBack(value[i])
-		if q.tail.isSplitNeed() {
+		if q.isSplitNeed(q.tail) {
 			q.splitNode(q.tail)
 		}
 	}
@@ -60,8 +138,8 @@ func (q *QuickList) LPush(value [][]byte) int {
 
 	for i := 0; i < len(value); i++ {
 		q.head.lp.AppendFront(value[i])
-		if q.head.isSplitNeed() {
-			q.splitNode(q.tail)
+		if q.isSplitNeed(q.head) {
+			q.splitNode(q.head)
 		}
 	}
 
@@ -198,7 +276,7 @@ func (q *QuickList) LPop(count int) [][]byte {
 
 func (q *QuickList) splitNode(n *quickNode) {
 	count := n.lp.count()
-	if count <= splitLimit {
+	if count < 2 || !q.isSplitNeed(n) {
 		return
 	}
 	mid := count / 2
@@ -223,7 +301,7 @@ func (q *QuickList) splitNode(n *quickNode) {
 }
 
 func (q *QuickList) mergeNode(n *quickNode) {
-	if n.prev != nil && n.count()+n.prev.count() <= splitLimit {
+	if n.prev != nil && q.canMerge(n.prev, n) {
 		for _, v := range n.lp.Values() {
 			n.prev.lp.AppendBack(v)
 		}
@@ -235,7 +313,7 @@ func (q *QuickList) mergeNode(n *quickNode) {
 		}
 		return
 	}
-	if n.next != nil && n.count()+n.next.count() <= splitLimit {
+	if n.next != nil && q.canMerge(n, n.next) {
 		for _, v := range n.next.lp.Values() {
 			n.lp.AppendBack(v)
 		}
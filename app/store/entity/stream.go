@@ -44,19 +44,43 @@ type StreamEntity struct {
 	Entries    []StreamEntry
 	LastMillis int
 	LastSeq    int
-	notify     chan struct{}
+
+	// EntriesAdded는 지금까지 XADD로 더한 엔트리 수입니다 (RDB entries_added)
+	EntriesAdded int
 }
 
-func (s *StreamEntity) Expired() bool {
-	return false
+// Copy는 엔트리의 ID와 필드까지 새로 만들어 원본과 메모리를 공유하지 않게 합니다
+func (s *StreamEntity) Copy() Entity {
+	c := &StreamEntity{LastMillis: s.LastMillis, LastSeq: s.LastSeq, EntriesAdded: s.EntriesAdded, Entries: make([]StreamEntry, len(s.Entries))}
+	for i, entry := range s.Entries {
+		c.Entries[i] = StreamEntry{Fields: append([]FieldValue(nil), entry.Fields...)}
+		if entry.Id != nil {
+			id := *entry.Id
+			c.Entries[i].Id = &id
+		}
+	}
+	return c
 }
 
-func NewStreamEntity() *StreamEntity {
-	return &StreamEntity{LastMillis: 0, LastSeq: 0, Entries: make([]StreamEntry, 0), notify: make(chan struct{}, 1)}
+func (s *StreamEntity) MemoryUsage() int {
+	n := streamOverhead
+	for _, entry := range s.Entries {
+		n += StreamEntrySize(entry)
+	}
+	return n
 }
 
-func (s *StreamEntity) Notify() chan struct{} {
-	return s.notify
+// StreamEntrySize는 엔트리 하나가 MemoryUsage에서 차지하는 크기입니다 (XADD가 달라진 만큼만 더할 때 씁니다)
+func StreamEntrySize(entry StreamEntry) int {
+	n := streamEntryOverhead
+	for _, f := range entry.Fields {
+		n += len(f.Key) + len(f.Value) + streamFieldOverhead
+	}
+	return n
+}
+
+func NewStreamEntity() *StreamEntity {
+	return &StreamEntity{LastMillis: 0, LastSeq: 0, Entries: make([]StreamEntry, 0)}
 }
 
 func parseStreamId(id string) (*StreamId, error) {
@@ -166,11 +190,8 @@ func (s *StreamEntity) ParseBound(id string) (*StreamId, error) {
 	case "+":
 		return &MaxID, nil
 	case "$":
-		lastEntry := &s.Entries[len(s.Entries)-1]
-		if lastEntry != nil {
-			return lastEntry.Id, nil
-		}
-		return &MinID, nil
+		// 비어 있는 스트림이면 마지막으로 만든 ID(없으면 0-0) 이후를 기다립니다
+		return &StreamId{Millis: s.LastMillis, Seq: s.LastSeq}, nil
 	default:
 		parsedId, err := parseBound(id)
 		if err != nil {
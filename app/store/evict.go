@@ -0,0 +1,193 @@
+package store
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// Eviction은 maxmemory 관련 설정입니다
+type Eviction struct {
+	MaxMemory    int64  // 0이면 제한하지 않습니다
+	Policy       string // maxmemory-policy
+	Samples      int    // maxmemory-samples
+	LFULogFactor int
+	LFUDecayTime int // 분 단위, 0이면 LFU 카운터를 줄이지 않습니다
+}
+
+// volatile이면 만료 시각이 있는 키만 쫓아냅니다
+func (p Eviction) volatile() bool {
+	return strings.HasPrefix(p.Policy, "volatile-")
+}
+
+// defaultEviction은 SetEviction을 부르기 전에 쓰는 Redis 기본값입니다
+var defaultEviction = Eviction{Policy: "noeviction", Samples: 5, LFULogFactor: 10, LFUDecayTime: 1}
+
+// SetEviction은 maxmemory 관련 설정을 적용합니다
+func (d *Databases) SetEviction(policy Eviction) {
+	d.shared.eviction.Store(&policy)
+}
+
+func (sh *shared) evictionPolicy() Eviction {
+	if policy := sh.eviction.Load(); policy != nil {
+		return *policy
+	}
+	return defaultEviction
+}
+
+// evictionPoolSize는 축출 후보 풀의 크기입니다 (Redis EVPOOL_SIZE)
+const evictionPoolSize = 16
+
+// evictionCandidate는 축출 풀의 후보입니다. score가 클수록 먼저 쫓아냅니다
+type evictionCandidate struct {
+	score uint64
+	db    int
+	key   string
+}
+
+// Evict는 사용량이 maxmemory 아래로 내려갈 때까지 키를 쫓아내고, 쫓아낸 키를 DB 번호별로 반환합니다 (Redis performEvictions)
+// noeviction이거나 더 쫓아낼 키가 없는데도 maxmemory를 넘으면 ok가 false입니다
+// CLIENT PAUSE 중에는 데이터셋을 바꾸지 않도록 쫓아내지 않고, 명령어도 거부하지 않습니다
+func (d *Databases) Evict() (evicted map[int][]string, ok bool) {
+	policy := d.shared.evictionPolicy()
+	if policy.MaxMemory == 0 || d.shared.usedMemory.Load() <= policy.MaxMemory {
+		return nil, true
+	}
+	if d.shared.expirationPaused() {
+		return nil, true
+	}
+	if policy.Policy == "noeviction" {
+		return nil, false
+	}
+
+	evicted = make(map[int][]string)
+	lazy := d.shared.lazyfreePolicy().Eviction
+	for d.shared.usedMemory.Load() > policy.MaxMemory {
+		var index int
+		var key string
+		var found bool
+		if strings.HasSuffix(policy.Policy, "-random") {
+			index, key, found = d.evictRandom(policy.volatile(), lazy)
+		} else {
+			index, key, found = d.evictFromPool(policy, lazy)
+		}
+		if !found {
+			return evicted, false
+		}
+		evicted[index] = append(evicted[index], key)
+		d.shared.evictedKeys.Add(1)
+	}
+	return evicted, true
+}
+
+// evictFromPool은 풀을 채운 뒤 점수가 가장 높은 후보부터 아직 남아 있는 키 하나를 쫓아냅니다
+func (d *Databases) evictFromPool(policy Eviction, lazy bool) (int, string, bool) {
+	d.fillPool(policy)
+	for len(d.pool) > 0 {
+		c := d.pool[len(d.pool)-1]
+		d.pool = d.pool[:len(d.pool)-1]
+
+		// 풀에 넣은 뒤에 지워졌거나 만료 시각이 사라진 키는 건너뜁니다
+		store := d.dbs[c.db]
+		store.mu.Lock()
+		_, exists := store.items.Get(c.key)
+		if policy.volatile() {
+			_, exists = store.expires[c.key]
+		}
+		if exists {
+			store.deleteKey(c.key, lazy)
+			store.notify(config.NotifyEvicted, "evicted", c.key)
+		}
+		store.mu.Unlock()
+		if exists {
+			return c.db, c.key, true
+		}
+	}
+	return 0, "", false
+}
+
+// fillPool은 DB마다 maxmemory-samples개 키를 뽑아 점수가 높은 후보를 풀에 모읍니다 (Redis evictionPoolPopulate)
+// 풀은 호출 사이에도 남아서, 전체를 훑지 않고도 샘플이 쌓일수록 실제 LRU/LFU에 가까워집니다
+func (d *Databases) fillPool(policy Eviction) {
+	now := time.Now()
+	for index, store := range d.dbs {
+		store.mu.RLock()
+		add := func(key string, obj *object) {
+			d.addCandidate(evictionCandidate{score: evictionScore(policy, obj, store.expires[key], now), db: index, key: key})
+		}
+		if policy.volatile() {
+			store.sampleExpires(policy.Samples, func(key string, _ time.Time) {
+				if obj, ok := store.items.Get(key); ok {
+					add(key, obj)
+				}
+			})
+		} else {
+			store.items.Sample(policy.Samples, add)
+		}
+		store.mu.RUnlock()
+	}
+}
+
+// evictionScore는 정책에 따른 후보 점수입니다 (LRU는 유휴 시간, LFU는 255-카운터, TTL은 만료가 이를수록 큽니다)
+func evictionScore(policy Eviction, obj *object, expireAt time.Time, now time.Time) uint64 {
+	switch {
+	case strings.HasSuffix(policy.Policy, "-lru"):
+		return uint64(max(now.UnixMilli()-obj.access.Load(), 0))
+	case strings.HasSuffix(policy.Policy, "-lfu"):
+		return 255 - uint64(obj.lfuCounter(policy.LFUDecayTime))
+	default:
+		return math.MaxUint64 - uint64(max(expireAt.UnixMilli(), 0))
+	}
+}
+
+// addCandidate는 점수 오름차순인 풀에 후보를 넣습니다. 풀이 가득 차면 점수가 가장 낮은 후보를 밀어냅니다
+func (d *Databases) addCandidate(c evictionCandidate) {
+	if len(d.pool) == evictionPoolSize && c.score <= d.pool[0].score {
+		return
+	}
+	if i := slices.IndexFunc(d.pool, func(p evictionCandidate) bool { return p.db == c.db && p.key == c.key }); i >= 0 {
+		d.pool = slices.Delete(d.pool, i, i+1)
+	}
+	if len(d.pool) == evictionPoolSize {
+		d.pool = slices.Delete(d.pool, 0, 1)
+	}
+	i, _ := slices.BinarySearchFunc(d.pool, c.score, func(p evictionCandidate, score uint64) int {
+		return cmp.Compare(p.score, score)
+	})
+	d.pool = slices.Insert(d.pool, i, c)
+}
+
+// evictRandom은 DB를 돌아가며 무작위 키 하나를 쫓아냅니다 (allkeys-random, volatile-random)
+func (d *Databases) evictRandom(volatile, lazy bool) (int, string, bool) {
+	for range d.dbs {
+		index := d.nextEvictDB
+		d.nextEvictDB = (d.nextEvictDB + 1) % len(d.dbs)
+
+		store := d.dbs[index]
+		store.mu.Lock()
+		key, found := "", false
+		if volatile {
+			for k := range store.expires {
+				key, found = k, true
+				break
+			}
+		} else {
+			store.items.Sample(1, func(k string, _ *object) {
+				key, found = k, true
+			})
+		}
+		if found {
+			store.deleteKey(key, lazy)
+			store.notify(config.NotifyEvicted, "evicted", key)
+		}
+		store.mu.Unlock()
+		if found {
+			return index, key, true
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// ExpireCondition은 EXPIRE 계열 명령어의 NX, XX, GT, LT 옵션입니다 (XX와 GT/LT는 함께 쓸 수 있어 비트로 나타냅니다)
+type ExpireCondition int
+
+const (
+	ExpireNX ExpireCondition = 1 << iota // 만료 시각이 없을 때만
+	ExpireXX                             // 만료 시각이 있을 때만
+	ExpireGT                             // 새 만료 시각이 더 늦을 때만 (만료 시각이 없으면 무한대로 봅니다)
+	ExpireLT                             // 새 만료 시각이 더 이를 때만
+)
+
+// Expire는 key의 만료 시각을 at으로 정하고, 적용했으면 true를 반환합니다
+// at이 이미 지났으면 키를 바로 지웁니다
+func (store *Store) Expire(key string, at time.Time, cond ExpireCondition) bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.lookupWrite(key) == nil {
+		return false
+	}
+
+	cur, hasTTL := store.expires[key]
+	if cond&ExpireNX != 0 && hasTTL {
+		return false
+	}
+	if cond&ExpireXX != 0 && !hasTTL {
+		return false
+	}
+	if cond&ExpireGT != 0 && (!hasTTL || !at.After(cur)) {
+		return false
+	}
+	if cond&ExpireLT != 0 && hasTTL && !at.Before(cur) {
+		return false
+	}
+
+	if !at.After(time.Now()) {
+		store.deleteKey(key, store.shared.lazyfreePolicy().Expire)
+		store.notify(config.NotifyGeneric, "del", key)
+		return true
+	}
+	store.expires[key] = at
+	store.notify(config.NotifyGeneric, "expire", key)
+	return true
+}
+
+// ExpireTime은 key의 만료 시각을 반환합니다
+// 키가 없으면 exists가 false이고, 만료 시각이 없으면 zero value입니다
+func (store *Store) ExpireTime(key string) (at time.Time, exists bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	if store.get(key) == nil {
+		return time.Time{}, false
+	}
+	return store.expires[key], true
+}
+
+// Persist는 key의 만료 시각을 지우고, 지웠으면 true를 반환합니다
+func (store *Store) Persist(key string) bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.lookupWrite(key) == nil {
+		return false
+	}
+	if _, ok := store.expires[key]; !ok {
+		return false
+	}
+	delete(store.expires, key)
+	store.notify(config.NotifyGeneric, "persist", key)
+	return true
+}
+
+// activeExpire는 만료 시각이 있는 키를 samples개씩 골라 만료된 키를 지우고, 지운 키를 반환합니다
+// 샘플의 25%보다 많이 만료되어 있으면 deadline까지 반복합니다 (Databases.ActiveExpireCycle에서 호출)
+func (store *Store) activeExpire(samples int, deadline time.Time) (deleted []string, sampled, expired int) {
+	for {
+		n, stale := 0, 0
+		store.mu.Lock()
+		lazy := store.shared.lazyfreePolicy().Expire
+		now := time.Now()
+		store.sampleExpires(samples, func(key string, at time.Time) {
+			n++
+			if now.After(at) {
+				store.deleteKey(key, lazy)
+				store.notify(config.NotifyExpired, "expired", key)
+				deleted = append(deleted, key)
+				stale++
+			}
+		})
+		store.mu.Unlock()
+
+		sampled += n
+		expired += stale
+		if n == 0 || stale*4 <= n || time.Now().After(deadline) {
+			return deleted, sampled, expired
+		}
+	}
+}
+
+// sampleExpires는 만료 시각이 있는 키 중 최대 n개에 fn을 호출합니다 (mu를 잡은 상태로 호출, fn 안에서 그 키를 지워도 됩니다)
+// Go map 순회는 시작 버킷과 버킷 안 시작 위치만 무작위이고 그 뒤로는 차례대로 돌기 때문에, 고르게 뽑은 표본이 아니라
+// 무작위 위치부터 이어지는 키 묶음입니다. dict.Sample과 같이 능동 만료와 volatile 축출처럼 근사로 충분한 곳에만 씁니다
+func (store *Store) sampleExpires(n int, fn func(key string, at time.Time)) {
+	if n <= 0 {
+		return
+	}
+	for key, at := range store.expires {
+		fn(key, at)
+		if n--; n == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/glob"
+)
+
+// Del은 keys를 지우고 실제로 지운 키 수를 반환합니다 (DEL, UNLINK)
+// unlink이거나 lazyfree-lazy-user-del이 켜져 있으면 큰 값은 백그라운드에서 해제합니다
+// 키를 기다리던 클라이언트는 깨우지 않으므로 다음에 데이터가 들어올 때까지 계속 기다립니다
+func (store *Store) Del(keys []string, unlink bool) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	lazy := unlink || store.shared.lazyfreePolicy().UserDel
+	n := 0
+	for _, key := range keys {
+		// 만료됐지만 남아 있는 키는 접근할 때처럼 expired로 지우고 지운 수에는 넣지 않습니다
+		if store.lookupWrite(key) == nil {
+			continue
+		}
+		n++
+		store.notify(config.NotifyGeneric, "del", key)
+		store.deleteKey(key, lazy)
+	}
+	return n
+}
+
+// Exists는 존재하는 키 수를 반환합니다 (같은 키를 여러 번 주면 그만큼 셉니다)
+// 있는지만 보므로 LRU/LFU 접근 정보는 바꾸지 않습니다
+func (store *Store) Exists(keys []string) int {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	n := 0
+	for _, key := range keys {
+		if store.lookup(key) != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Touch는 keys의 마지막 접근 시각과 LFU 카운터를 갱신하고 존재하는 키 수를 반환합니다
+func (store *Store) Touch(keys []string) int {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	n := 0
+	for _, key := range keys {
+		if store.get(key) != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Rename은 src를 dst로 옮깁니다. 엔티티를 그대로 옮기므로 만료 시각도 유지됩니다
+// nx면 dst가 이미 있을 때 옮기지 않고 false를 반환합니다
+func (store *Store) Rename(src, dst string, nx bool) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.get(src) == nil {
+		return false, fmt.Errorf("ERR no such key")
+	}
+	if nx && store.get(dst) != nil {
+		return false, nil
+	}
+	if src == dst {
+		return !nx, nil
+	}
+
+	at, hasTTL := store.expires[src]
+	store.putObject(dst, store.removeKey(src))
+	if hasTTL {
+		store.expires[dst] = at
+	}
+	store.signalKey(dst)
+	store.notify(config.NotifyGeneric, "rename_from", src)
+	store.notify(config.NotifyGeneric, "rename_to", dst)
+	return true, nil
+}
+
+// Keys는 pattern에 맞는 키를 모두 반환합니다 (KEYS)
+func (store *Store) Keys(pattern string) []string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	matchAll := pattern == "*"
+	keys := make([]string, 0)
+	store.items.Range(func(key string, _ *object) bool {
+		if !store.expired(key) && (matchAll || glob.Match(pattern, key, false)) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	return keys
+}
+
+// Scan은 cursor부터 버킷을 훑어 count개쯤 키를 모으고 다음 커서를 반환합니다 (SCAN)
+// pattern과 typ(빈 문자열이면 모든 타입)은 모은 뒤에 거르므로 반환하는 키가 count보다 적을 수 있습니다
+func (store *Store) Scan(cursor uint64, pattern string, count int, typ string) (uint64, []string) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	// 빈 버킷이 이어질 때 한 번에 너무 오래 돌지 않도록 Redis와 같이 count*10 버킷까지만 봅니다
+	maxIterations := count * 10
+	visited := 0
+	keys := make([]string, 0, count)
+	for {
+		cursor = store.items.Scan(cursor, func(key string, obj *object) {
+			visited++
+			if store.expired(key) {
+				return
+			}
+			if pattern != "*" && !glob.Match(pattern, key, false) {
+				return
+			}
+			if typ != "" && typeName(obj.value) != typ {
+				return
+			}
+			keys = append(keys, key)
+		})
+		maxIterations--
+		if cursor == 0 || maxIterations == 0 || visited >= count {
+			return cursor, keys
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/store/dict"
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
+)
+
+// LazyFree는 lazyfree-lazy-* 설정입니다. 켜진 경우의 삭제는 큰 값을 백그라운드에서 해제합니다
+type LazyFree struct {
+	Eviction  bool // maxmemory로 쫓아낸 키
+	Expire    bool // 만료된 키
+	ServerDel bool // 덮어쓰기처럼 서버가 암묵적으로 지우는 값
+	UserDel   bool // DEL (켜면 UNLINK처럼 동작합니다)
+}
+
+// lazyfreeThreshold보다 해제할 할당이 많은 값만 백그라운드로 넘깁니다 (Redis LAZYFREE_THRESHOLD)
+const lazyfreeThreshold = 64
+
+// freeEffort는 값을 해제할 때 끊어야 하는 할당 수를 어림합니다 (Redis lazyfreeGetFreeEffort)
+func freeEffort(entry entity.Entity) int {
+	switch e := entry.(type) {
+	case *entity.ListEntity:
+		return e.ValueData.NodeCount(lazyfreeThreshold)
+	case *entity.StreamEntity:
+		return len(e.Entries)
+	default:
+		return 1
+	}
+}
+
+// release는 값 안의 연결을 끊어 GC가 바로 거둘 수 있게 합니다
+func release(entry entity.Entity) {
+	switch e := entry.(type) {
+	case *entity.ListEntity:
+		e.ValueData.Release()
+	case *entity.StreamEntity:
+		clear(e.Entries)
+		e.Entries = nil
+	}
+}
+
+// SetLazyFree는 lazyfree-lazy-* 설정을 적용합니다
+func (d *Databases) SetLazyFree(policy LazyFree) {
+	d.shared.lazyfree.Store(&policy)
+}
+
+func (sh *shared) lazyfreePolicy() LazyFree {
+	if policy := sh.lazyfree.Load(); policy != nil {
+		return *policy
+	}
+	return LazyFree{}
+}
+
+// free는 키 공간에서 떼어 낸 값을 해제합니다 (mu를 잡은 상태로 호출)
+// lazy이고 값이 크면 잠금을 잡은 명령어가 기다리지 않도록 고루틴에서 해제합니다
+func (store *Store) free(entry entity.Entity, lazy bool) {
+	if !lazy || freeEffort(entry) <= lazyfreeThreshold {
+		return
+	}
+	sh := store.shared
+	sh.lazyfreePending.Add(1)
+	go func() {
+		release(entry)
+		sh.lazyfreePending.Add(-1)
+		sh.lazyfreed.Add(1)
+	}()
+}
+
+// freeDict는 FLUSHDB ASYNC, FLUSHALL ASYNC로 떼어 낸 테이블을 고루틴에서 해제합니다
+func (sh *shared) freeDict(items *dict.Dict[*object]) {
+	n := int64(items.Len())
+	if n == 0 {
+		return
+	}
+	sh.lazyfreePending.Add(n)
+	go func() {
+		items.Range(func(_ string, obj *object) bool {
+			release(obj.value)
+			sh.lazyfreePending.Add(-1)
+			sh.lazyfreed.Add(1)
+			return true
+		})
+	}()
+}
+
+// LazyFreeStats는 INFO의 lazyfree_pending_objects와 lazyfreed_objects를 반환합니다
+func (d *Databases) LazyFreeStats() (pending, freed int64) {
+	return d.shared.lazyfreePending.Load(), d.shared.lazyfreed.Load()
+}
@@ -0,0 +1,39 @@
+package store
+
+// addUsedMemory는 메모리 사용량 어림값에 delta를 더하고 최고치를 갱신합니다
+func (sh *shared) addUsedMemory(delta int64) {
+	used := sh.usedMemory.Add(delta)
+	for peak := sh.peakMemory.Load(); used > peak; peak = sh.peakMemory.Load() {
+		if sh.peakMemory.CompareAndSwap(peak, used) {
+			return
+		}
+	}
+}
+
+// MemoryStats는 INFO의 used_memory(키와 값의 어림값 합), used_memory_peak, evicted_keys를 반환합니다
+func (d *Databases) MemoryStats() (used, peak, evicted int64) {
+	return d.shared.usedMemory.Load(), d.shared.peakMemory.Load(), d.shared.evictedKeys.Load()
+}
+
+// DBMemory는 MEMORY STATS의 DB별 항목입니다
+type DBMemory struct {
+	Index    int
+	Keys     int
+	Expires  int
+	Overhead int64 // 키 이름을 뺀 dict 엔트리와 object 헤더 (overhead.hashtable.main)
+}
+
+// DBMemory는 키가 있는 DB의 메모리 항목을 반환합니다
+func (d *Databases) DBMemory() []DBMemory {
+	var out []DBMemory
+	for i, db := range d.dbs {
+		db.mu.RLock()
+		keys, expires := db.items.Len(), len(db.expires)
+		db.mu.RUnlock()
+		if keys == 0 {
+			continue
+		}
+		out = append(out, DBMemory{Index: i, Keys: keys, Expires: expires, Overhead: int64(keys) * objectOverhead})
+	}
+	return out
+}
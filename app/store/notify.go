@@ -0,0 +1,40 @@
+package store
+
+import (
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// Publisher는 pub/sub 채널에 메시지를 보내는 함수입니다 (키 공간 알림에 씁니다)
+type Publisher func(channel, message string) int
+
+// SetNotifyKeyspaceEvents는 notify-keyspace-events 설정을 적용합니다
+func (d *Databases) SetNotifyKeyspaceEvents(flags config.KeyspaceEvents) {
+	d.shared.notifyFlags.Store(int64(flags))
+}
+
+// SetPublisher는 키 공간 알림을 보낼 pub/sub을 연결합니다
+func (d *Databases) SetPublisher(publish Publisher) {
+	d.shared.publish.Store(&publish)
+}
+
+// notify는 key에 event가 일어났음을 알립니다 (Redis notifyKeyspaceEvent)
+// class가 notify-keyspace-events에 켜져 있을 때만 __keyspace@<db>__:<key>로 event를, __keyevent@<db>__:<event>로 key를 보냅니다
+func (store *Store) notify(class config.KeyspaceEvents, event, key string) {
+	flags := config.KeyspaceEvents(store.shared.notifyFlags.Load())
+	if flags&class == 0 {
+		return
+	}
+	publish := store.shared.publish.Load()
+	if publish == nil {
+		return
+	}
+	db := strconv.Itoa(store.index)
+	if flags&config.NotifyKeyspace != 0 {
+		(*publish)("__keyspace@"+db+"__:"+key, event)
+	}
+	if flags&config.NotifyKeyevent != 0 {
+		(*publish)("__keyevent@"+db+"__:"+event, key)
+	}
+}
@@ -0,0 +1,152 @@
+package store
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
+	"github.com/codecrafters-io/redis-starter-go/app/store/entity/list"
+)
+
+// object는 키 공간 dict에 저장하는 값과 메타데이터입니다 (Redis robj)
+// 접근 정보는 읽기 잠금만 잡은 명령어도 갱신하므로 atomic으로 둡니다
+type object struct {
+	value entity.Entity
+	size  int64 // 키와 값의 메모리 사용량 어림값 (mu로 보호)
+
+	access atomic.Int64  // 마지막 접근 시각 (unix 밀리초, LRU)
+	lfu    atomic.Uint32 // lfuMinutes() << 8 | 카운터: 8~23번 비트는 마지막 접근 시각(16비트 분), 하위 8비트는 로그 카운터 (LFU)
+}
+
+const (
+	// objectOverhead는 dict 엔트리와 object 헤더의 크기입니다
+	objectOverhead = 56
+
+	// lfuInitVal은 새 키의 LFU 카운터입니다. 0에서 시작하면 새 키가 바로 쫓겨나므로 조금 높게 둡니다
+	lfuInitVal = 5
+)
+
+func newObject(key string, value entity.Entity) *object {
+	obj := &object{value: value, size: objectSize(key, value)}
+	obj.access.Store(time.Now().UnixMilli())
+	obj.lfu.Store(lfuMinutes()<<8 | lfuInitVal)
+	return obj
+}
+
+// objectSize는 key와 value가 차지하는 메모리 어림값입니다
+func objectSize(key string, value entity.Entity) int64 {
+	return int64(objectOverhead + len(key) + value.MemoryUsage())
+}
+
+// touch는 키에 접근했음을 기록합니다 (Redis updateLFU와 LRU 시계)
+func (obj *object) touch(policy Eviction) {
+	obj.access.Store(time.Now().UnixMilli())
+	counter := lfuLogIncr(obj.lfuCounter(policy.LFUDecayTime), policy.LFULogFactor)
+	obj.lfu.Store(lfuMinutes()<<8 | uint32(counter))
+}
+
+// idle은 마지막 접근 뒤로 지난 시간입니다
+func (obj *object) idle() time.Duration {
+	return time.Duration(time.Now().UnixMilli()-obj.access.Load()) * time.Millisecond
+}
+
+// lfuMinutes는 LFU 카운터에 함께 적는 16비트 분 단위 시계입니다
+func lfuMinutes() uint32 {
+	return uint32(time.Now().Unix()/60) & 0xffff
+}
+
+// lfuCounter는 lfu-decay-time분마다 1씩 줄어든 카운터입니다 (Redis LFUDecrAndReturn)
+func (obj *object) lfuCounter(decayTime int) uint8 {
+	v := obj.lfu.Load()
+	counter := uint8(v & 0xff)
+	if decayTime <= 0 {
+		return counter
+	}
+	elapsed := (lfuMinutes() - v>>8) & 0xffff
+	periods := elapsed / uint32(decayTime)
+	if periods >= uint32(counter) {
+		return 0
+	}
+	return counter - uint8(periods)
+}
+
+// lfuLogIncr는 카운터가 클수록 낮은 확률로 1 올립니다 (Redis LFULogIncr)
+// lfu-log-factor가 클수록 255에 이르기까지 더 많은 접근이 필요합니다
+func lfuLogIncr(counter uint8, logFactor int) uint8 {
+	if counter == 255 {
+		return counter
+	}
+	base := max(float64(counter)-lfuInitVal, 0)
+	if rand.Float64() < 1/(base*float64(logFactor)+1) {
+		counter++
+	}
+	return counter
+}
+
+// embstrSizeLimit보다 짧은 문자열은 Redis가 object와 한 번에 할당하는 embstr 인코딩입니다
+const embstrSizeLimit = 44
+
+// ObjectInfo는 OBJECT 명령어가 보여 주는 키 정보입니다
+type ObjectInfo struct {
+	Encoding string
+	Idle     time.Duration
+	Freq     int // lfu-decay-time에 따라 줄인 LFU 카운터
+}
+
+// Object는 key의 인코딩과 접근 정보를 반환합니다 (OBJECT)
+// 들여다보기만 하는 명령어라서 접근 정보를 바꾸지 않습니다
+func (store *Store) Object(key string) (ObjectInfo, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	obj := store.lookup(key)
+	if obj == nil {
+		return ObjectInfo{}, false
+	}
+	return ObjectInfo{
+		Encoding: encoding(obj.value, store.shared.encodingPolicy()),
+		Idle:     obj.idle(),
+		Freq:     int(obj.lfuCounter(store.shared.evictionPolicy().LFUDecayTime)),
+	}, true
+}
+
+// encoding은 OBJECT ENCODING에 보여 줄 값의 내부 표현 이름입니다
+// 리스트는 원소가 list-max-listpack-size 제한 안의 listpack 하나에 들어가면 listpack, 넘치면 quicklist입니다
+func encoding(entry entity.Entity, enc Encoding) string {
+	switch e := entry.(type) {
+	case *entity.StringEntity:
+		if len(e.ValueData) <= 20 {
+			if _, err := strconv.ParseInt(e.ValueData, 10, 64); err == nil {
+				return "int"
+			}
+		}
+		if len(e.ValueData) <= embstrSizeLimit {
+			return "embstr"
+		}
+		return "raw"
+	case *entity.ListEntity:
+		if list.NodeFits(enc.ListMaxListpackSize, e.ValueData.Len(), e.ValueData.ListpackBytes()) {
+			return "listpack"
+		}
+		return "quicklist"
+	case *entity.StreamEntity:
+		return "stream"
+	default:
+		return "unknown"
+	}
+}
+
+// MemoryUsage는 key와 값이 차지하는 메모리를 바이트 단위로 어림합니다 (MEMORY USAGE)
+// samples가 0보다 크면 리스트와 스트림은 앞쪽 samples개 원소의 평균으로 어림합니다
+func (store *Store) MemoryUsage(key string, samples int) (int64, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	obj := store.lookup(key)
+	if obj == nil {
+		return 0, false
+	}
+	return int64(objectOverhead + len(key) + entity.SampledMemoryUsage(obj.value, samples)), true
+}
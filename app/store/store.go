@@ -6,53 +6,241 @@ import (
 	"sync"
 	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/store/dict"
 	"github.com/codecrafters-io/redis-starter-go/app/store/entity"
 )
 
+// Store는 논리 데이터베이스 하나입니다 (SELECT로 고르는 DB, databases.go의 Databases가 묶습니다)
 type Store struct {
-	items map[string]entity.Entity
+	// items는 SCAN 커서를 지원하도록 Go map 대신 dict를 씁니다
+	items *dict.Dict[*object]
 	mu    sync.RWMutex
+
+	// expires는 만료 시각이 있는 키만 담는 인덱스입니다 (mu로 보호)
+	expires map[string]time.Time
+
+	// used는 이 DB에 있는 키의 메모리 사용량 어림값 합입니다 (mu로 보호, FLUSHDB와 SWAPDB에 씁니다)
+	used int64
+
+	// shared는 모든 논리 데이터베이스가 함께 쓰는 만료 상태와 통계입니다
+	shared *shared
+
+	// index는 SELECT 번호입니다 (키 공간 알림 채널 이름에 씁니다)
+	index int
+
+	// waiters는 BLPOP, XREAD BLOCK이 기다리는 키별 알림 채널입니다 (mu로 보호)
+	waiters map[string][]chan struct{}
 }
 
-func NewStore() *Store {
-	return &Store{items: make(map[string]entity.Entity)}
+func newStore(sh *shared, index int) *Store {
+	return &Store{
+		shared:  sh,
+		index:   index,
+		items:   dict.New[*object](),
+		expires: make(map[string]time.Time),
+		waiters: make(map[string][]chan struct{}),
+	}
+}
+
+// lookup은 만료되지 않은 키의 object를 반환합니다. 접근 시각은 바꾸지 않습니다 (mu를 잡은 상태로 호출)
+func (store *Store) lookup(key string) *object {
+	obj, ok := store.items.Get(key)
+	if !ok || store.expired(key) {
+		return nil
+	}
+	return obj
+}
+
+// get은 만료되지 않은 키만 반환하고 LRU/LFU 접근 정보를 갱신합니다 (mu를 잡은 상태로 호출)
+// 읽기 잠금만 잡은 경로에서도 쓸 수 있도록 만료된 키를 지우지는 않습니다
+func (store *Store) get(key string) entity.Entity {
+	obj := store.lookup(key)
+	if obj == nil {
+		return nil
+	}
+	obj.touch(store.shared.evictionPolicy())
+	return obj.value
+}
+
+// expired는 key의 만료 시각이 지났는지 확인합니다 (mu를 잡은 상태로 호출)
+func (store *Store) expired(key string) bool {
+	at, ok := store.expires[key]
+	return ok && time.Now().After(at)
+}
+
+// lookupWrite는 쓰기 명령어가 쓸 키를 반환합니다. 만료된 키는 지우고 nil을 반환합니다 (mu 쓰기 잠금을 잡은 상태로 호출)
+// 곧 새 값으로 덮어쓸 키라서 CLIENT PAUSE 중에도 지웁니다
+func (store *Store) lookupWrite(key string) entity.Entity {
+	if store.expired(key) {
+		store.expireKey(key)
+	}
+	obj, ok := store.items.Get(key)
+	if !ok {
+		return nil
+	}
+	obj.touch(store.shared.evictionPolicy())
+	return obj.value
+}
+
+// setKey는 key에 entry를 저장하고 기존 만료 시각을 지웁니다 (mu를 잡은 상태로 호출)
+func (store *Store) setKey(key string, entry entity.Entity) {
+	store.putObject(key, newObject(key, entry))
+}
+
+// putObject는 key에 obj를 저장하고 기존 만료 시각을 지웁니다 (mu를 잡은 상태로 호출)
+// 덮어쓴 값은 lazyfree-lazy-server-del에 따라 해제합니다. RENAME, MOVE는 접근 정보를 유지하도록 object를 그대로 옮깁니다
+// 새로 생긴 키면 new 알림을 보냅니다
+func (store *Store) putObject(key string, obj *object) {
+	old := store.removeKey(key)
+	if old != nil && old != obj {
+		store.free(old.value, store.shared.lazyfreePolicy().ServerDel)
+	}
+	obj.size = objectSize(key, obj.value)
+	store.items.Set(key, obj)
+	delete(store.expires, key)
+	store.account(obj.size)
+	if old == nil {
+		store.notify(config.NotifyNew, "new", key)
+	}
+}
+
+// removeKey는 key와 만료 시각을 함께 지우고 떼어 낸 object를 반환합니다 (mu를 잡은 상태로 호출)
+// 값은 다른 곳으로 옮길 때처럼 해제하지 않습니다
+func (store *Store) removeKey(key string) *object {
+	obj, ok := store.items.Get(key)
+	if !ok {
+		return nil
+	}
+	store.items.Delete(key)
+	delete(store.expires, key)
+	store.account(-obj.size)
+	return obj
+}
+
+// deleteKey는 key를 지우고 값을 해제합니다. lazy면 큰 값은 백그라운드에서 해제합니다 (mu를 잡은 상태로 호출)
+func (store *Store) deleteKey(key string, lazy bool) {
+	if obj := store.removeKey(key); obj != nil {
+		store.free(obj.value, lazy)
+	}
+}
+
+// grow는 제자리에서 바뀐 key 값의 크기 변화를 반영합니다 (mu를 잡은 상태로 호출)
+func (store *Store) grow(key string, delta int) {
+	if obj, ok := store.items.Get(key); ok {
+		obj.size += int64(delta)
+		store.account(int64(delta))
+	}
+}
+
+// account는 DB와 서버 전체의 메모리 사용량 어림값에 delta를 더합니다 (mu를 잡은 상태로 호출)
+func (store *Store) account(delta int64) {
+	store.used += delta
+	store.shared.addUsedMemory(delta)
+}
+
+// expireIfNeeded는 만료된 key를 지웁니다. CLIENT PAUSE 중에는 지우지 않습니다
+func (store *Store) expireIfNeeded(key string) {
+	if store.expirationPaused() {
+		return
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.expired(key) {
+		store.expireKey(key)
+	}
+}
+
+// expireKey는 만료된 key를 지우고 expired 알림을 보냅니다 (mu 쓰기 잠금을 잡은 상태로 호출)
+// 지운 키는 Databases.LazyExpired로 넘겨 레플리카에 DEL로 전파하게 합니다
+func (store *Store) expireKey(key string) {
+	store.deleteKey(key, store.shared.lazyfreePolicy().Expire)
+	store.shared.expiredKeys.Add(1)
+	store.shared.addLazyExpired(store.index, key)
+	store.notify(config.NotifyExpired, "expired", key)
+}
+
+// waitKeys는 keys 중 하나에 데이터가 들어오면 신호를 받을 채널을 등록합니다 (mu를 잡은 상태로 호출)
+func (store *Store) waitKeys(keys ...string) chan struct{} {
+	ch := make(chan struct{}, 1)
+	for _, key := range keys {
+		store.waiters[key] = append(store.waiters[key], ch)
+	}
+	return ch
+}
+
+// stopWaiting은 waitKeys로 등록한 채널을 지웁니다 (mu를 잡은 상태로 호출)
+func (store *Store) stopWaiting(ch chan struct{}, keys ...string) {
+	for _, key := range keys {
+		list := store.waiters[key]
+		for i, c := range list {
+			if c == ch {
+				list = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(store.waiters, key)
+		} else {
+			store.waiters[key] = list
+		}
+	}
+}
+
+// signalKey는 key를 기다리는 클라이언트를 모두 깨웁니다 (mu를 잡은 상태로 호출)
+// 깨어난 클라이언트는 키를 다시 확인하고, 가져갈 데이터가 없으면 계속 기다립니다
+func (store *Store) signalKey(key string) {
+	for _, ch := range store.waiters[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
 }
 
 func (store *Store) Get(key string) (string, bool) {
 	store.mu.RLock()
-	entry, ok := store.items[key]
+	obj, ok := store.items.Get(key)
+	expired := ok && store.expired(key)
+	if ok && !expired {
+		obj.touch(store.shared.evictionPolicy())
+	}
 	store.mu.RUnlock()
 	if !ok {
 		return "", false
 	}
-	if entry.Expired() {
-		store.mu.Lock()
-		if cur, exists := store.items[key]; exists && cur == entry {
-			delete(store.items, key)
-		}
-		store.mu.Unlock()
+	if expired {
+		store.expireIfNeeded(key)
 		return "", false
 	}
-	stringEntity, ok := entry.(*entity.StringEntity)
+	stringEntity, ok := obj.value.(*entity.StringEntity)
 	if !ok {
 		return "", false
 	}
 	return stringEntity.Value(), true
 }
 
+func (store *Store) expirationPaused() bool {
+	return store.shared.expirationPaused()
+}
+
 func (store *Store) Set(key, value string, expire time.Time) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
-	entry := &entity.StringEntity{ValueData: value, Expire: expire}
-	store.items[key] = entry
+	store.setKey(key, &entity.StringEntity{ValueData: value})
+	store.notify(config.NotifyString, "set", key)
+	if !expire.IsZero() {
+		store.expires[key] = expire
+		store.notify(config.NotifyGeneric, "expire", key)
+	}
 }
 
 func (store *Store) ensureList(key string) *entity.ListEntity {
-	if e, ok := store.items[key].(*entity.ListEntity); ok {
+	if e, ok := store.lookupWrite(key).(*entity.ListEntity); ok {
 		return e
 	}
-	le := entity.NewListEntity()
-	store.items[key] = le
+	le := entity.NewListEntity(store.shared.encodingPolicy().ListMaxListpackSize)
+	store.setKey(key, le)
 	return le
 }
 
@@ -63,12 +251,11 @@ func (store *Store) RPush(key string, value [][]byte) (int, bool) {
 	listEntity := store.ensureList(key)
 	wasEmpty := listEntity.ValueData.Len() == 0
 	n := listEntity.ValueData.RPush(value)
+	store.grow(key, entity.ListElementsSize(value))
+	store.notify(config.NotifyList, "rpush", key)
 
 	if wasEmpty && n > 0 {
-		select {
-		case listEntity.Notify() <- struct{}{}:
-		default:
-		}
+		store.signalKey(key)
 	}
 
 	return n, true
@@ -81,12 +268,11 @@ func (store *Store) LPush(key string, value [][]byte) (int, bool) {
 	listEntity := store.ensureList(key)
 	wasEmpty := listEntity.ValueData.Len() == 0
 	n := listEntity.ValueData.LPush(value)
+	store.grow(key, entity.ListElementsSize(value))
+	store.notify(config.NotifyList, "lpush", key)
 
 	if wasEmpty && n > 0 {
-		select {
-		case listEntity.Notify() <- struct{}{}:
-		default:
-		}
+		store.signalKey(key)
 	}
 
 	return n, true
@@ -97,11 +283,12 @@ func (store *Store) LRange(key string, startPos int, endPos int) ([][]byte, bool
 	store.mu.RLock()
 	defer store.mu.RUnlock()
 
-	if store.items[key] == nil {
+	entry := store.get(key)
+	if entry == nil {
 		return [][]byte{}, true
 	}
 
-	listEntity, ok := store.items[key].(*entity.ListEntity)
+	listEntity, ok := entry.(*entity.ListEntity)
 	if !ok {
 		return nil, false
 	}
@@ -113,11 +300,12 @@ func (store *Store) LLen(key string) (int, bool) {
 	store.mu.RLock()
 	defer store.mu.RUnlock()
 
-	if store.items[key] == nil {
+	entry := store.get(key)
+	if entry == nil {
 		return 0, true
 	}
 
-	listEntity, ok := store.items[key].(*entity.ListEntity)
+	listEntity, ok := entry.(*entity.ListEntity)
 	if !ok {
 		return 0, false
 	}
@@ -130,75 +318,95 @@ func (store *Store) LPop(key string, count int) ([][]byte, bool) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	if store.items[key] == nil {
+	entry := store.lookupWrite(key)
+	if entry == nil {
 		return nil, true
 	}
 
-	listEntity, ok := store.items[key].(*entity.ListEntity)
+	listEntity, ok := entry.(*entity.ListEntity)
 	if !ok {
 		return nil, false
 	}
 
-	return listEntity.ValueData.LPop(count), true
+	return store.popList(key, listEntity, count), true
 }
 
-func (store *Store) BLPop(key string, timeOut time.Duration) ([]byte, bool) {
-	store.mu.Lock()
-
-	if store.items[key] == nil {
-		store.items[key] = entity.NewListEntity()
+// popList는 리스트 앞에서 count개를 꺼내고, 비면 키를 지웁니다 (mu를 잡은 상태로 호출)
+func (store *Store) popList(key string, listEntity *entity.ListEntity, count int) [][]byte {
+	out := listEntity.ValueData.LPop(count)
+	if len(out) > 0 {
+		store.notify(config.NotifyList, "lpop", key)
+	}
+	if listEntity.ValueData.Len() == 0 {
+		store.removeKey(key)
+		store.notify(config.NotifyGeneric, "del", key)
+	} else {
+		store.grow(key, -entity.ListElementsSize(out))
 	}
+	return out
+}
 
-	listEntity, ok := store.items[key].(*entity.ListEntity)
-	if !ok {
-		return []byte{}, false
+// BLPop은 key에 원소가 들어올 때까지 기다렸다가 꺼냅니다 (timeOut이 0이면 무한히 기다립니다)
+// 기다리는 동안 키가 지워지거나 다른 타입이 되어도 계속 기다리고, 리스트가 생기면 깨어납니다
+func (store *Store) BLPop(key string, timeOut time.Duration) ([]byte, bool) {
+	var deadline <-chan time.Time
+	if timeOut > 0 {
+		timer := time.NewTimer(timeOut)
+		defer timer.Stop()
+		deadline = timer.C
 	}
 
-	if listEntity.ValueData.Len() > 0 {
-		val := listEntity.ValueData.LPop(1)
+	store.mu.Lock()
+	notify := store.waitKeys(key)
+	defer func() {
+		store.mu.Lock()
+		store.stopWaiting(notify, key)
 		store.mu.Unlock()
-		if len(val) == 0 {
-			return nil, true
+	}()
+
+	for first := true; ; first = false {
+		switch e := store.get(key).(type) {
+		case *entity.ListEntity:
+			if e.ValueData.Len() > 0 {
+				out := store.popList(key, e, 1)
+				// 남은 원소가 있으면 함께 깨어났다 놓친 다른 클라이언트가 가져가게 합니다
+				if e.ValueData.Len() > 0 {
+					store.signalKey(key)
+				}
+				store.mu.Unlock()
+				return out[0], true
+			}
+		case nil:
+		default:
+			if first {
+				store.mu.Unlock()
+				return []byte{}, false
+			}
 		}
-		return val[0], true
-	}
-	notify := listEntity.Notify()
-	store.mu.Unlock()
+		store.mu.Unlock()
 
-	if timeOut > 0 {
 		select {
 		case <-notify:
-		case <-time.After(timeOut):
+		case <-deadline:
 			return nil, true
 		}
-	} else {
-		<-notify
-	}
-
-	store.mu.Lock()
-	defer store.mu.Unlock()
-
-	listEntity, ok = store.items[key].(*entity.ListEntity)
-	if !ok {
-		return nil, true
-	}
-
-	out := listEntity.ValueData.LPop(1)
-	if len(out) == 0 {
-		return nil, true
+		store.mu.Lock()
 	}
-	return out[0], true
 }
 
+// Type은 key의 타입 이름입니다. 키를 읽은 것으로 치지 않으므로 접근 정보를 바꾸지 않습니다
 func (store *Store) Type(key string) string {
 	store.mu.RLock()
 	defer store.mu.RUnlock()
 
-	entry := store.items[key]
-	if entry == nil {
-		return "none"
+	if obj := store.lookup(key); obj != nil {
+		return typeName(obj.value)
 	}
+	return typeName(nil)
+}
 
+// typeName은 TYPE 명령어와 SCAN TYPE에서 쓰는 타입 이름입니다 (entry가 nil이면 "none")
+func typeName(entry entity.Entity) string {
 	switch entry.(type) {
 	case *entity.StringEntity:
 		return "string"
@@ -212,11 +420,11 @@ func (store *Store) Type(key string) string {
 }
 
 func (store *Store) ensureStream(key string) *entity.StreamEntity {
-	if streamEntity, ok := store.items[key].(*entity.StreamEntity); ok {
+	if streamEntity, ok := store.lookupWrite(key).(*entity.StreamEntity); ok {
 		return streamEntity
 	}
 	streamEntity := entity.NewStreamEntity()
-	store.items[key] = streamEntity
+	store.setKey(key, streamEntity)
 	return streamEntity
 }
 
@@ -231,11 +439,10 @@ func (store *Store) XAdd(key string, id string, fields []entity.FieldValue) (str
 	}
 	entry := entity.StreamEntry{Id: generateId, Fields: fields}
 	streamEntity.Entries = append(streamEntity.Entries, entry)
-
-	select {
-	case streamEntity.Notify() <- struct{}{}:
-	default:
-	}
+	streamEntity.EntriesAdded++
+	store.grow(key, entity.StreamEntrySize(entry))
+	store.signalKey(key)
+	store.notify(config.NotifyStream, "xadd", key)
 
 	return fmt.Sprintf("%d-%d", generateId.Millis, generateId.Seq), nil
 }
@@ -253,10 +460,19 @@ func filterEntries(entries []entity.StreamEntry, startId, endId *entity.StreamId
 	return result
 }
 
-func (store *Store) XRange(key, start, end string) ([]entity.StreamEntry, error) {
+// lookupStream은 key의 스트림을 반환하고, 없으면 저장하지 않은 빈 스트림을 반환합니다 (mu를 잡은 상태로 호출)
+// 읽기 명령어가 빈 키를 만들지 않도록 ensureStream 대신 씁니다
+func (store *Store) lookupStream(key string) *entity.StreamEntity {
+	if streamEntity, ok := store.get(key).(*entity.StreamEntity); ok {
+		return streamEntity
+	}
+	return entity.NewStreamEntity()
+}
 
+func (store *Store) XRange(key, start, end string) ([]entity.StreamEntry, error) {
 	store.mu.RLock()
-	streamEntity := store.ensureStream(key)
+	defer store.mu.RUnlock()
+	streamEntity := store.lookupStream(key)
 
 	startId, err := streamEntity.ParseBound(start)
 	if err != nil {
@@ -267,104 +483,78 @@ func (store *Store) XRange(key, start, end string) ([]entity.StreamEntry, error)
 		return nil, err
 	}
 
-	entries := append([]entity.StreamEntry(nil), streamEntity.Entries...) // 복사
-	store.mu.RUnlock()
-
-	return filterEntries(entries, startId, endId), nil
+	return filterEntries(streamEntity.Entries, startId, endId), nil
 }
 
-// TODO 최적화 필요
-func (store *Store) XRead(timeout time.Duration, keys []string, ids []string) ([][]entity.StreamEntry, error) {
-	result := make([][]entity.StreamEntry, len(ids))
-	streamIds := make([]*entity.StreamId, len(ids))
-	store.mu.RLock()
-	for _, key := range keys {
-		stream := store.ensureStream(key)
-		for j, id := range ids {
-			bound, err := stream.ParseBound(id)
-			if err != nil {
-				return result, err
-			}
-			streamIds[j] = bound
+// XRead는 keys[i]의 ids[i] 이후 엔트리를 읽습니다
+// block이면 엔트리가 생길 때까지 기다리며 (timeout이 0이면 무한히), 시간이 지나면 nil을 반환합니다
+func (store *Store) XRead(block bool, timeout time.Duration, keys []string, ids []string) ([][]entity.StreamEntry, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	bounds := make([]*entity.StreamId, len(keys))
+	for i, key := range keys {
+		bound, err := store.lookupStream(key).ParseBound(ids[i])
+		if err != nil {
+			return nil, err
 		}
+		bounds[i] = bound
 	}
-	store.mu.RUnlock()
 
 	checkStreams := func() ([][]entity.StreamEntry, bool) {
-		store.mu.RLock()
-		defer store.mu.RUnlock()
 		hasData := false
-		tmpResults := make([][]entity.StreamEntry, len(keys))
+		results := make([][]entity.StreamEntry, len(keys))
 		for i, key := range keys {
-			stream := store.ensureStream(key)
-			for _, e := range stream.Entries {
-				if e.Id != nil && !e.Id.Under(streamIds[i]) {
-					tmpResults[i] = append(tmpResults[i], e)
+			for _, e := range store.lookupStream(key).Entries {
+				if e.Id != nil && !e.Id.Under(bounds[i]) {
+					results[i] = append(results[i], e)
 					hasData = true
 				}
 			}
 		}
-		return tmpResults, hasData
+		return results, hasData
 	}
 
 	results, hasData := checkStreams()
-	if hasData {
+	if hasData || !block {
 		return results, nil
 	}
 
-	notifies := make([]<-chan struct{}, len(keys))
-	for i, key := range keys {
-		stream := store.ensureStream(key)
-		notifies[i] = stream.Notify()
-	}
-
-	merged := make(chan struct{})
-	var once sync.Once
-	for _, ch := range notifies {
-		// 기존 신호 제거
-		for {
-			select {
-			case <-ch:
-			default:
-				goto Done
-			}
-		}
-	Done:
-		// 고루틴 생성
-		go func(c <-chan struct{}) {
-			<-c
-			once.Do(func() { close(merged) })
-		}(ch)
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
 	}
 
-	if timeout > 0 {
+	notify := store.waitKeys(keys...)
+	defer store.stopWaiting(notify, keys...)
+	for {
+		store.mu.Unlock()
 		select {
-		case <-merged:
-		case <-time.After(timeout):
+		case <-notify:
+		case <-deadline:
+			store.mu.Lock()
 			return nil, nil
 		}
-	} else {
-		fmt.Println("LIMIT")
-		<-merged
-		fmt.Println("MERGED!")
+		store.mu.Lock()
+		if results, hasData := checkStreams(); hasData {
+			return results, nil
+		}
 	}
-
-	streams, _ := checkStreams()
-	fmt.Println("END!!")
-	fmt.Println(timeout)
-	fmt.Println(streams)
-	return streams, nil
 }
 
 func (store *Store) Incr(key string) (int, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	if store.items[key] == nil {
-		store.items[key] = &entity.StringEntity{ValueData: "0"}
+	entry := store.lookupWrite(key)
+	if entry == nil {
+		entry = &entity.StringEntity{ValueData: "0"}
+		store.setKey(key, entry)
 	}
 
-	stringEntity, ok := store.items[key].(*entity.StringEntity)
+	stringEntity, ok := entry.(*entity.StringEntity)
 
 	if !ok {
 		return 0, fmt.Errorf("ERR value is not an integer or out of range")
@@ -377,6 +567,9 @@ func (store *Store) Incr(key string) (int, error) {
 	}
 	intValue++
 
+	before := len(stringEntity.ValueData)
 	stringEntity.ValueData = strconv.Itoa(intValue)
+	store.grow(key, len(stringEntity.ValueData)-before)
+	store.notify(config.NotifyString, "incrby", key)
 	return intValue, nil
 }
@@ -29,6 +29,13 @@ func (tx *Transaction) AddCommand(cmd Cmd) bool {
 		}
 	}
 
+	// 인수는 연결의 읽기 버퍼를 가리키므로 큐에 넣기 전에 복사합니다
+	args := make([][]byte, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		args[i] = append([]byte(nil), arg...)
+	}
+	cmd.Args = args
+
 	tx.CmdQueue = append(tx.CmdQueue, cmd)
 	return true
 }
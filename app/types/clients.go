@@ -0,0 +1,271 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// ReplyMode는 CLIENT REPLY로 정하는 응답 전송 방식입니다
+type ReplyMode int
+
+const (
+	ReplyOn   ReplyMode = iota
+	ReplyOff            // 응답을 보내지 않습니다
+	ReplySkip           // 지금 처리 중인 명령어의 응답만 보내지 않습니다
+)
+
+// ClientRegistry는 서버에 연결된 클라이언트 목록입니다 (CLIENT LIST, CLIENT KILL 등)
+// 연결 고루틴이 추가하고 지우므로 mu로 보호합니다
+type ClientRegistry struct {
+	mu      sync.Mutex
+	nextID  int64
+	clients map[int64]*ConnContext
+}
+
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{clients: make(map[int64]*ConnContext)}
+}
+
+// Add는 연결에 새 ID를 붙여 목록에 넣습니다
+func (r *ClientRegistry) Add(ctx *ConnContext) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	ctx.id = r.nextID
+	r.clients[ctx.id] = ctx
+}
+
+func (r *ClientRegistry) Remove(ctx *ConnContext) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, ctx.id)
+}
+
+func (r *ClientRegistry) Get(id int64) (*ConnContext, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ctx, ok := r.clients[id]
+	return ctx, ok
+}
+
+// List는 연결된 클라이언트를 ID 순서로 반환합니다
+func (r *ClientRegistry) List() []*ConnContext {
+	r.mu.Lock()
+	list := make([]*ConnContext, 0, len(r.clients))
+	for _, ctx := range r.clients {
+		list = append(list, ctx)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	return list
+}
+
+func (r *ClientRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.clients)
+}
+
+// ID는 CLIENT ID로 보여 주는 연결 번호입니다 (ClientRegistry.Add에서 정해진 뒤 바뀌지 않습니다)
+func (ctx *ConnContext) ID() int64 {
+	return ctx.id
+}
+
+func (ctx *ConnContext) Name() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.name
+}
+
+func (ctx *ConnContext) SetName(name string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.name = name
+}
+
+// DB는 SELECT로 고른 논리 데이터베이스 번호입니다
+func (ctx *ConnContext) DB() int {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.db
+}
+
+// SelectDB는 이 연결이 쓸 데이터베이스를 바꿉니다 (범위 검사는 호출하는 쪽에서 합니다)
+func (ctx *ConnContext) SelectDB(db int) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.db = db
+}
+
+// SetSubscriptions는 구독 중인 채널과 패턴 수를 기록합니다
+// 구독이 하나라도 있으면 출력 버퍼 제한을 pubsub 클래스로 바꾸고, 모두 풀면 normal로 되돌립니다
+func (ctx *ConnContext) SetSubscriptions(subs, psubs int) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.subs, ctx.psubs = subs, psubs
+	if ctx.class == config.ClientReplica {
+		return
+	}
+	class := config.ClientNormal
+	if subs+psubs > 0 {
+		class = config.ClientPubSub
+	}
+	if ctx.class != class {
+		ctx.class = class
+		ctx.softSince = time.Time{}
+	}
+}
+
+// Subscribed는 pub/sub 모드인지 반환합니다 (구독 관련 명령어와 PING 등만 받습니다)
+func (ctx *ConnContext) Subscribed() bool {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.subs+ctx.psubs > 0
+}
+
+// Touch는 명령어를 읽은 시각과 아직 처리하지 않은 쿼리 버퍼 크기를 기록합니다
+func (ctx *ConnContext) Touch(queryBuf int) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.lastInteraction = time.Now()
+	ctx.queryBuf = queryBuf
+}
+
+// SetLastCommand는 CLIENT LIST의 cmd 항목에 보여 줄 명령어 이름("client|list" 형식)을 기록합니다
+func (ctx *ConnContext) SetLastCommand(name string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.lastCommand = name
+}
+
+// SetBlocked는 BLPOP, XREAD BLOCK처럼 데이터를 기다리는 중인지 표시합니다
+func (ctx *ConnContext) SetBlocked(blocked bool) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.blocked = blocked
+}
+
+func (ctx *ConnContext) Blocked() bool {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.blocked
+}
+
+// SetReplyMode는 CLIENT REPLY ON|OFF|SKIP을 적용합니다
+// SKIP은 다음 명령어 하나의 응답만 건너뛰며, OFF 상태에서는 아무 효과가 없습니다
+func (ctx *ConnContext) SetReplyMode(mode ReplyMode) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	switch mode {
+	case ReplySkip:
+		if ctx.reply != ReplyOff {
+			ctx.skipNext = true
+		}
+	default:
+		ctx.reply = mode
+		ctx.skipNext = false
+	}
+}
+
+// FinishCommand는 명령어 하나를 처리한 뒤 CLIENT REPLY SKIP 상태를 다음 명령어로 넘깁니다
+func (ctx *ConnContext) FinishCommand() {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.reply == ReplySkip {
+		ctx.reply = ReplyOn
+	}
+	if ctx.skipNext {
+		ctx.reply = ReplySkip
+		ctx.skipNext = false
+	}
+}
+
+// Type은 CLIENT LIST TYPE, CLIENT KILL TYPE에서 쓰는 클라이언트 종류입니다
+func (ctx *ConnContext) Type() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.master {
+		return "master"
+	}
+	return ctx.class.String()
+}
+
+// Age는 연결된 뒤 지난 시간입니다
+func (ctx *ConnContext) Age() time.Duration {
+	return time.Since(ctx.createdAt)
+}
+
+// Idle은 마지막으로 명령어를 읽은 뒤 지난 시간입니다
+func (ctx *ConnContext) Idle() time.Duration {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return time.Since(ctx.lastInteraction)
+}
+
+// Info는 CLIENT LIST와 CLIENT INFO에 보여 줄 한 줄입니다
+func (ctx *ConnContext) Info() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	now := time.Now()
+	user := acl.DefaultUserName
+	if ctx.user != nil {
+		user = ctx.user.Name()
+	}
+	cmd := ctx.lastCommand
+	if cmd == "" {
+		cmd = "NULL"
+	}
+	multi := -1
+	if ctx.tx.IsInTransaction() {
+		multi = len(ctx.tx.GetCommands())
+	}
+
+	return fmt.Sprintf("id=%d addr=%s laddr=%s name=%s age=%d idle=%d flags=%s db=%d sub=%d psub=%d multi=%d qbuf=%d omem=%d cmd=%s user=%s",
+		ctx.id,
+		ConnAddr(ctx.Conn),
+		ctx.Conn.LocalAddr().String(),
+		ctx.name,
+		int64(now.Sub(ctx.createdAt).Seconds()),
+		int64(now.Sub(ctx.lastInteraction).Seconds()),
+		ctx.flags(),
+		ctx.db,
+		ctx.subs,
+		ctx.psubs,
+		multi,
+		ctx.queryBuf,
+		len(ctx.out)+ctx.inflight,
+		cmd,
+		user)
+}
+
+// flags는 CLIENT LIST의 flags 항목입니다 (mu를 잡은 상태로 호출)
+func (ctx *ConnContext) flags() string {
+	var sb strings.Builder
+	if ctx.master {
+		sb.WriteByte('M')
+	}
+	if ctx.class == config.ClientReplica {
+		sb.WriteByte('S')
+	}
+	if ctx.tx.IsInTransaction() {
+		sb.WriteByte('x')
+	}
+	if ctx.blocked {
+		sb.WriteByte('b')
+	}
+	if ctx.subs+ctx.psubs > 0 {
+		sb.WriteByte('P')
+	}
+	if sb.Len() == 0 {
+		return "N"
+	}
+	return sb.String()
+}
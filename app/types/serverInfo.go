@@ -22,6 +22,7 @@ type ServerInfo struct {
 	masterServerIp             string
 	masterServerPort           int
 	offset                     int
+	stats                      *Stats
 }
 
 func (s *ServerInfo) GetMasterAddress() string {
@@ -29,7 +30,7 @@ func (s *ServerInfo) GetMasterAddress() string {
 }
 
 func (s *ServerInfo) GetInfo() string {
-	return fmt.Sprintf("role:%s\nconnected_slaves:%d\nmaster_replid:%s\nmaster_repl_offset:%d\nsecond_repl_offset:%d\nrepl_backlog_active:%d\nrepl_backlog_size:%d\nrepl_backlog_first_byte_offset:%d\nrepl_backlog_histlen:%d",
+	return fmt.Sprintf("role:%s\r\nconnected_slaves:%d\r\nmaster_replid:%s\r\nmaster_repl_offset:%d\r\nsecond_repl_offset:%d\r\nrepl_backlog_active:%d\r\nrepl_backlog_size:%d\r\nrepl_backlog_first_byte_offset:%d\r\nrepl_backlog_histlen:%d",
 		s.role,
 		s.connectedSlave,
 		s.masterReplId,
@@ -54,6 +55,10 @@ func createMasterReplId() string {
 	return string(b)
 }
 
+func (s *ServerInfo) GetStats() *Stats {
+	return s.stats
+}
+
 func (s *ServerInfo) IsSlave() bool {
 	return s.role == "slave"
 }
@@ -85,6 +90,7 @@ func NewServerInfo(serverPort int, masterServerInfo string) *ServerInfo {
 			masterReplId:     createMasterReplId(),
 			masterReplOffset: 0,
 			ServerPort:       serverPort,
+			stats:            &Stats{},
 		}
 	}
 
@@ -101,5 +107,6 @@ func NewServerInfo(serverPort int, masterServerInfo string) *ServerInfo {
 		masterServerIp:   masterServerIp,
 		masterServerPort: masterServerPort,
 		ServerPort:       serverPort,
+		stats:            &Stats{},
 	}
 }
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Stats는 INFO stats 섹션에 보고하는 서버 전체 통계입니다
+type Stats struct {
+	OutputBufferLimitDisconnections atomic.Int64
+	TimeoutDisconnections           atomic.Int64 // timeout 설정으로 닫은 유휴 연결 수
+	RejectedConnections             atomic.Int64 // maxclients, protected mode로 거절한 연결 수
+}
+
+func (s *Stats) Info() string {
+	return fmt.Sprintf("rejected_connections:%d\r\nclient_output_buffer_limit_disconnections:%d\r\nclient_timeout_disconnections:%d",
+		s.RejectedConnections.Load(),
+		s.OutputBufferLimitDisconnections.Load(),
+		s.TimeoutDisconnections.Load())
+}
+
+// Reset은 CONFIG RESETSTAT으로 누적 통계를 0으로 되돌립니다
+func (s *Stats) Reset() {
+	s.OutputBufferLimitDisconnections.Store(0)
+	s.TimeoutDisconnections.Store(0)
+	s.RejectedConnections.Store(0)
+}
@@ -1,9 +1,13 @@
 package types
 
 import (
+	"fmt"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
 	"github.com/codecrafters-io/redis-starter-go/app/transaction"
 )
 
@@ -11,24 +15,284 @@ type ConnContext struct {
 	Conn net.Conn
 	mu   sync.Mutex
 	tx   *transaction.Transaction
+
+	out             []byte // 아직 소켓에 쓰지 않은 응답
+	inflight        int    // writer 고루틴이 전송 중인 바이트 수
+	wake            chan struct{}
+	closed          chan struct{}
+	closeOnce       sync.Once
+	closeAfterReply bool
+	master          bool // 레플리카 쪽에서 본 마스터 연결이면 true (일반 응답을 보내지 않습니다)
+	authenticated   bool
+	user            *acl.User // nil이면 모든 권한 (마스터 연결)
+
+	class     config.ClientClass
+	limits    config.OutputLimiter
+	softSince time.Time // soft 제한을 처음 넘은 시각
+	stats     *Stats
+
+	// CLIENT LIST 등에 보여 줄 정보 (clients.go)
+	id              int64
+	name            string
+	createdAt       time.Time
+	lastInteraction time.Time
+	lastCommand     string
+	blocked         bool
+	queryBuf        int
+	reply           ReplyMode
+	skipNext        bool // CLIENT REPLY SKIP 다음 명령어의 응답을 건너뜁니다
+
+	db int // SELECT로 고른 논리 데이터베이스 번호
+
+	// SUBSCRIBE, PSUBSCRIBE로 구독 중인 채널과 패턴 수 (하나라도 있으면 pub/sub 모드)
+	subs  int
+	psubs int
 }
 
-func NewConnContext(conn net.Conn, transaction *transaction.Transaction) *ConnContext {
-	return &ConnContext{
-		Conn: conn,
-		tx:   transaction,
+func NewConnContext(conn net.Conn, transaction *transaction.Transaction, limits config.OutputLimiter, stats *Stats) *ConnContext {
+	ctx := &ConnContext{
+		Conn:   conn,
+		tx:     transaction,
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+		limits: limits,
+		stats:  stats,
+
+		createdAt:       time.Now(),
+		lastInteraction: time.Now(),
 	}
+	go ctx.writeLoop()
+	return ctx
 }
 
+// 한 번 크게 늘어난 출력 버퍼는 이 크기를 넘으면 비운 뒤 놓아 줍니다
+const maxIdleOutputBuffer = 64 * 1024
+
+// Write는 응답을 출력 버퍼에 쌓아 둡니다
+// 실제 전송은 Flush에서 일어나며, 이벤트 루프가 파이프라인 묶음이 끝날 때 호출합니다
+// 출력 버퍼 제한을 넘으면 응답을 버리고 연결을 닫습니다
 func (ctx *ConnContext) Write(message []byte) int {
 	ctx.mu.Lock()
 	defer ctx.mu.Unlock()
 
-	n, err := ctx.Conn.Write(message)
-	if err != nil {
+	if ctx.master || ctx.reply != ReplyOn {
+		return 0
+	}
+	return ctx.write(message)
+}
+
+// ForceWrite는 마스터 연결에도 응답을 보냅니다 (REPLCONF ACK 등)
+func (ctx *ConnContext) ForceWrite(message []byte) int {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.write(message)
+}
+
+// write는 Write와 ForceWrite의 공통 부분입니다 (mu를 잡은 상태로 호출)
+func (ctx *ConnContext) write(message []byte) int {
+	if ctx.isClosed() || ctx.closeAfterReply {
 		return 0
 	}
-	return n
+
+	ctx.out = append(ctx.out, message...)
+	if ctx.outputLimitReached() {
+		ctx.out = nil
+		if ctx.stats != nil {
+			ctx.stats.OutputBufferLimitDisconnections.Add(1)
+		}
+		ctx.close()
+		return 0
+	}
+	return len(message)
+}
+
+// outputLimitReached는 클라이언트 클래스의 출력 버퍼 제한을 넘었는지 확인합니다 (mu를 잡은 상태로 호출)
+func (ctx *ConnContext) outputLimitReached() bool {
+	if ctx.limits == nil {
+		return false
+	}
+	limit := ctx.limits.OutputBufferLimit(ctx.class)
+	size := int64(len(ctx.out) + ctx.inflight)
+
+	if limit.Hard > 0 && size >= limit.Hard {
+		return true
+	}
+
+	if limit.Soft > 0 && size >= limit.Soft {
+		now := time.Now()
+		if ctx.softSince.IsZero() {
+			ctx.softSince = now
+			return false
+		}
+		return now.Sub(ctx.softSince) > time.Duration(limit.SoftSeconds)*time.Second
+	}
+
+	ctx.softSince = time.Time{}
+	return false
+}
+
+// Flush는 출력 버퍼에 쌓인 응답을 writer 고루틴에 넘겨 전송하게 합니다
+// 느린 클라이언트 때문에 이벤트 루프가 멈추지 않도록 실제 소켓 쓰기는 writer 고루틴이 합니다
+// 이벤트 루프 밖(블로킹 명령어의 고루틴 등)에서 Write한 경우에는 직접 호출해야 합니다
+func (ctx *ConnContext) Flush() error {
+	ctx.mu.Lock()
+	pending := len(ctx.out) > 0
+	ctx.mu.Unlock()
+
+	if pending {
+		ctx.signal()
+	}
+	return nil
+}
+
+// CloseAfterReply는 쌓여 있는 응답을 모두 보낸 뒤 연결을 닫습니다
+func (ctx *ConnContext) CloseAfterReply() {
+	ctx.mu.Lock()
+	ctx.closeAfterReply = true
+	ctx.mu.Unlock()
+	ctx.signal()
+}
+
+// Close는 보내지 못한 응답을 버리고 즉시 연결을 닫습니다
+func (ctx *ConnContext) Close() {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.close()
+}
+
+func (ctx *ConnContext) close() {
+	ctx.closeOnce.Do(func() {
+		close(ctx.closed)
+		_ = ctx.Conn.Close()
+	})
+}
+
+// IsClosed는 연결이 이미 닫혔는지 반환합니다
+func (ctx *ConnContext) IsClosed() bool {
+	return ctx.isClosed()
+}
+
+func (ctx *ConnContext) isClosed() bool {
+	select {
+	case <-ctx.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ctx *ConnContext) signal() {
+	select {
+	case ctx.wake <- struct{}{}:
+	default:
+	}
+}
+
+// writeLoop는 Flush 신호를 받을 때마다 출력 버퍼를 통째로 가져가 소켓에 씁니다
+func (ctx *ConnContext) writeLoop() {
+	draining := false
+	for {
+		if !draining {
+			select {
+			case <-ctx.wake:
+			case <-ctx.closed:
+				return
+			}
+		}
+
+		ctx.mu.Lock()
+		buf := ctx.out
+		ctx.out = nil
+		ctx.inflight = len(buf)
+		ctx.mu.Unlock()
+
+		if len(buf) > 0 {
+			if _, err := ctx.Conn.Write(buf); err != nil {
+				ctx.Close()
+				return
+			}
+		}
+
+		ctx.mu.Lock()
+		ctx.inflight = 0
+		if ctx.out == nil && cap(buf) <= maxIdleOutputBuffer {
+			ctx.out = buf[:0]
+		}
+		draining = ctx.closeAfterReply
+		finished := draining && len(ctx.out) == 0
+		ctx.mu.Unlock()
+
+		if finished {
+			ctx.Close()
+			return
+		}
+	}
+}
+
+// SetClass는 출력 버퍼 제한을 고를 때 쓰는 클라이언트 종류를 바꿉니다
+func (ctx *ConnContext) SetClass(class config.ClientClass) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.class = class
+	ctx.softSince = time.Time{}
+}
+
+// SetMaster는 이 연결을 마스터에서 오는 복제 스트림으로 표시합니다
+// Redis와 같이 복제된 명령어에 대한 응답은 마스터로 보내지 않습니다
+func (ctx *ConnContext) SetMaster() {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.master = true
+	ctx.authenticated = true
+	ctx.user = nil
+}
+
+// IsAuthenticated는 AUTH로 인증을 마친 연결인지 반환합니다
+func (ctx *ConnContext) IsAuthenticated() bool {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.authenticated
+}
+
+// SetUser는 연결의 사용자를 정합니다. authenticated가 false면 AUTH를 기다립니다
+func (ctx *ConnContext) SetUser(user *acl.User, authenticated bool) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.user = user
+	ctx.authenticated = authenticated
+}
+
+func (ctx *ConnContext) User() *acl.User {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.user
+}
+
+// Username은 ACL WHOAMI와 ACL LOG에 보여 줄 사용자 이름입니다
+func (ctx *ConnContext) Username() string {
+	if user := ctx.User(); user != nil {
+		return user.Name()
+	}
+	return acl.DefaultUserName
+}
+
+// Addr는 클라이언트 목록 등에 보여 줄 연결 주소를 반환합니다
+func (ctx *ConnContext) Addr() string {
+	return ConnAddr(ctx.Conn)
+}
+
+// ConnAddr는 연결의 상대 주소를 반환합니다
+// 유닉스 소켓 연결은 상대 주소가 비어 있으므로 Redis와 같이 소켓 경로를 보여 줍니다
+func ConnAddr(conn net.Conn) string {
+	if conn.LocalAddr().Network() == "unix" {
+		return conn.LocalAddr().String()
+	}
+	return conn.RemoteAddr().String()
+}
+
+// ClientInfo는 ACL LOG 등에 남길 연결 정보입니다
+func (ctx *ConnContext) ClientInfo() string {
+	return fmt.Sprintf("addr=%s user=%s", ctx.Addr(), ctx.Username())
 }
 
 func (ctx *ConnContext) GetTransaction() *transaction.Transaction {
@@ -36,10 +300,12 @@ func (ctx *ConnContext) GetTransaction() *transaction.Transaction {
 }
 
 type CommandEvent struct {
-	Ctx     *ConnContext
-	Command string
-	Args    [][]byte
-	Raw     []byte
+	Ctx       *ConnContext
+	Command   string
+	Args      [][]byte
+	RawLen    int           // 복제 오프셋 계산에 쓰는 원본 RESP 바이트 길이
+	Done      chan struct{} // 처리가 끝나면 신호를 보냅니다 (Args가 가리키는 버퍼를 재사용해도 되는 시점)
+	Pipelined bool          // 같은 연결에서 이미 도착한 다음 명령어가 있으면 true (응답 전송을 미룹니다)
 }
 
 type Handler func(CommandEvent)
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/transaction"
+)
+
+func TestConnContextFlush(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	limits := config.DefaultOutputBufferLimits()
+	ctx := NewConnContext(server, transaction.NewTransaction(), &limits, &Stats{})
+	ctx.Write([]byte("+PONG\r\n"))
+	ctx.Write([]byte("+PONG\r\n"))
+	ctx.Flush()
+
+	buf := make([]byte, 14)
+	if _, err := io.ReadFull(client, buf); err != nil {
+		t.Fatalf("응답 읽기 실패: %v", err)
+	}
+	if string(buf) != "+PONG\r\n+PONG\r\n" {
+		t.Errorf("응답이 잘못됨. got=%q", buf)
+	}
+	ctx.Close()
+}
+
+func TestConnContextOutputBufferLimit(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	limits := config.DefaultOutputBufferLimits()
+	limits[config.ClientReplica] = config.OutputBufferLimit{Hard: 64}
+	stats := &Stats{}
+	ctx := NewConnContext(server, transaction.NewTransaction(), &limits, stats)
+	ctx.SetClass(config.ClientReplica)
+
+	// 아무도 읽지 않으므로 writer 고루틴은 첫 묶음을 보내다가 멈춥니다
+	chunk := make([]byte, 40)
+	ctx.Write(chunk)
+	ctx.Flush()
+	time.Sleep(10 * time.Millisecond)
+
+	if n := ctx.Write(chunk); n != 0 {
+		t.Errorf("hard 제한을 넘은 응답은 버려져야 함. got=%d", n)
+	}
+	if !ctx.isClosed() {
+		t.Errorf("hard 제한을 넘으면 연결이 닫혀야 함")
+	}
+	if got := stats.OutputBufferLimitDisconnections.Load(); got != 1 {
+		t.Errorf("출력 버퍼 제한 종료 횟수가 다름. got=%d, want=1", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -315,3 +316,47 @@ func TestReplConf(t *testing.T) {
 		return
 	}
 }
+
+func TestInlinePing(t *testing.T) {
+	fmt.Println("인라인 PING 테스트")
+	resp := sendAndReceive(t, "PING\r\n")
+	expected := "+PONG\r\n"
+	if resp != expected {
+		t.Errorf("인라인 PING 응답이 잘못됨. got=%q, want=%q", resp, expected)
+	}
+}
+
+func TestInlineQuotedArgs(t *testing.T) {
+	fmt.Println("인라인 따옴표 인수 테스트")
+	resp := sendAndReceive(t, "ECHO \"hello\\x21 \\\"world\\\"\"\r\n")
+	expected := "$14\r\nhello! \"world\"\r\n"
+	if resp != expected {
+		t.Errorf("인라인 ECHO 응답이 잘못됨. got=%q, want=%q", resp, expected)
+		return
+	}
+
+	resp = sendAndReceive(t, "ECHO 'it\\'s here'\n")
+	expected = "$9\r\nit's here\r\n"
+	if resp != expected {
+		t.Errorf("인라인 작은따옴표 응답이 잘못됨. got=%q, want=%q", resp, expected)
+	}
+}
+
+func TestProtocolErrorBulkLength(t *testing.T) {
+	fmt.Println("bulk 길이 제한 테스트")
+	resp := sendAndReceive(t, "*2\r\n$4\r\nECHO\r\n$999999999999\r\n")
+	expected := "-ERR Protocol error: invalid bulk length\r\n"
+	if resp != expected {
+		t.Errorf("bulk 길이 제한 응답이 잘못됨. got=%q, want=%q", resp, expected)
+	}
+}
+
+func TestPipelinedReplies(t *testing.T) {
+	fmt.Println("파이프라인 응답 묶음 테스트")
+	message := strings.Repeat("*1\r\n$4\r\nPING\r\n", 3) + "*2\r\n$4\r\nECHO\r\n$4\r\nlast\r\n"
+	resp := sendAndReceive(t, message)
+	expected := "+PONG\r\n+PONG\r\n+PONG\r\n$4\r\nlast\r\n"
+	if resp != expected {
+		t.Errorf("파이프라인 응답이 한 번에 오지 않음. got=%q, want=%q", resp, expected)
+	}
+}